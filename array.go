@@ -0,0 +1,122 @@
+package rlp
+
+import (
+	"reflect"
+)
+
+// Array is an RLP list with a fixed number of items, backed by a Go array.
+//
+// The A type must be an array type, for example [4]Uint. For any other type,
+// encoding and decoding fail with ErrUnsupportedType. Unlike TypedList, the
+// number of items is declared by the type, so the list does not have to be
+// prepared before decoding. During decoding, the number of items in the data
+// must match the length of the array, otherwise ErrUnexpectedNumberOfItems is
+// returned.
+//
+// The array elements are encoded and decoded in the same way as the items of
+// TypedList: the element type or a pointer to it must implement the Encoder
+// interface if the array is being encoded, and a pointer to the element type
+// must implement the Decoder interface if the array is being decoded, unless
+// the element type is a pointer type that implements it, in which case nil
+// elements are allocated during decoding. In particular, byte arrays are not
+// supported, because byte does not implement these interfaces. To encode a
+// byte array as a string, use Bytes20, Bytes32 or Bytes256.
+type Array[A any] struct {
+	value A
+}
+
+// Get returns the array.
+func (a Array[A]) Get() A {
+	return a.value
+}
+
+// Ptr returns a pointer to the array.
+func (a *Array[A]) Ptr() *A {
+	return &a.value
+}
+
+// Set sets the array.
+func (a *Array[A]) Set(value A) {
+	a.value = value
+}
+
+// EncodeRLP implements the Encoder interface.
+func (a Array[A]) EncodeRLP() ([]byte, error) {
+	return a.AppendRLP(nil)
+}
+
+// AppendRLP implements the Appender interface.
+func (a Array[A]) AppendRLP(dst []byte) ([]byte, error) {
+	start := len(dst)
+	dst = append(dst, 0)
+	err := a.eachItem(func(item any) error {
+		var err error
+		dst, err = appendItem(dst, item)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return finishList(dst, start)
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the array cannot be encoded, it returns 0.
+func (a Array[A]) EncodedSize() int {
+	n, _ := encodedSize(a)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (a Array[A]) eachItem(fn func(item any) error) error {
+	v, err := a.reflectValue()
+	if err != nil {
+		return err
+	}
+	// Trailing items that are omitted from the encoding are skipped, in the
+	// same way as by forEachItem.
+	n := v.Len()
+	for n > 0 && isOmitted(v.Index(n-1).Interface()) {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		// Elements whose type does not implement the Encoder interface are
+		// passed as pointers, in the same way as by encoderOf.
+		item := v.Index(i).Interface()
+		if _, ok := item.(Encoder); !ok {
+			item = v.Index(i).Addr().Interface()
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeRLP implements the Decoder interface.
+func (a *Array[A]) DecodeRLP(data []byte) (int, error) {
+	return a.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (a *Array[A]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	v, err := a.reflectValue()
+	if err != nil {
+		return 0, err
+	}
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = decoderOfValue(v.Index(i))
+	}
+	return decodeTypedListAs(data, &items, func() any { return nil }, false, reflect.TypeOf(a).Elem(), s)
+}
+
+// reflectValue returns the addressable reflection value of the array.
+func (a *Array[A]) reflectValue() (reflect.Value, error) {
+	v := reflect.ValueOf(&a.value).Elem()
+	if v.Kind() != reflect.Array {
+		return reflect.Value{}, ErrUnsupportedType
+	}
+	return v, nil
+}
@@ -0,0 +1,117 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestArrayEncode(t *testing.T) {
+	tests := []struct {
+		data    Encoder
+		want    []byte
+		wantErr error
+	}{
+		{data: Array[[0]Uint]{}, want: []byte{0xc0}},
+		{data: Array[[3]Uint]{value: [3]Uint{1, 2, 3}}, want: []byte{0xc3, 0x01, 0x02, 0x03}},
+		{data: Array[[2]String]{value: [2]String{"a", "dog"}}, want: []byte{0xc5, 'a', 0x83, 'd', 'o', 'g'}},
+		{data: Array[[2]byte]{value: [2]byte{0, 1}}, wantErr: ErrUnsupportedType},
+		{data: Array[[2]Optional[Uint, AbsentOmitted]]{}, want: []byte{0xc0}},
+		{data: Array[[2]List]{value: [2]List{{Uint(1)}, {}}}, want: []byte{0xc3, 0xc1, 0x01, 0xc0}},
+		{data: Array[[2]*Uint]{}, wantErr: ErrNilValue},
+		{data: Array[Uint]{}, wantErr: ErrUnsupportedType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := tt.data.(Sizer).EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestArrayDecode(t *testing.T) {
+	arrayType := reflect.TypeOf(Array[[3]Uint]{})
+	tests := []struct {
+		data     []byte
+		want     [3]Uint
+		wantErr  error
+		wantType reflect.Type
+	}{
+		{data: []byte{0xc3, 0x01, 0x02, 0x03}, want: [3]Uint{1, 2, 3}},
+		{data: []byte{0xc2, 0x01, 0x02}, wantErr: ErrUnexpectedNumberOfItems, wantType: arrayType},
+		{data: []byte{0xc4, 0x01, 0x02, 0x03, 0x04}, wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0xc3, 0x01, 0xc0, 0x03}, wantErr: ErrUnsupportedType, wantType: reflect.TypeOf(uint64(0))},
+		{data: []byte{0x80}, wantErr: ErrUnsupportedType, wantType: arrayType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var item Array[[3]Uint]
+			_, err := Decode(tt.data, &item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && item.Get() != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, item.Get())
+			}
+			var decErr *DecodeError
+			if tt.wantErr != nil && errors.As(err, &decErr) && decErr.Type != tt.wantType {
+				t.Fatalf("expected type %v, got %v", tt.wantType, decErr.Type)
+			}
+		})
+	}
+}
+
+func TestArrayInList(t *testing.T) {
+	list := VarTypedList[Array[[2]Uint]]{}
+	if _, err := Decode([]byte{0xc6, 0xc2, 0x01, 0x02, 0xc2, 0x03, 0x04}, &list); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(list) != 2 || list[0].Get() != [2]Uint{1, 2} || list[1].Get() != [2]Uint{3, 4} {
+		t.Fatalf("unexpected result %v", list)
+	}
+}
+
+func TestArrayDecodeWithOptions(t *testing.T) {
+	var item Array[[2]Uint]
+	_, err := DecodeWithOptions([]byte{0xc3, 0x01, 0x81, 0x02}, &item, DecodeOptions{})
+	if !errors.Is(err, ErrNonCanonicalEncoding) {
+		t.Fatalf("expected error %v, got %v", ErrNonCanonicalEncoding, err)
+	}
+	_, err = DecodeWithOptions([]byte{0xc3, 0x01, 0x81, 0x02}, &item, DecodeOptions{AllowNonCanonical: true})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if item.Get() != [2]Uint{1, 2} {
+		t.Fatalf("expected %v, got %v", [2]Uint{1, 2}, item.Get())
+	}
+}
+
+func TestArrayDecodeOmitted(t *testing.T) {
+	var item Array[[2]Optional[Uint, AbsentOmitted]]
+	if _, err := Decode([]byte{0xc1, 0x01}, &item); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v := item.Get(); !v[0].Valid() || v[0].Get() != 1 || v[1].Valid() {
+		t.Fatalf("unexpected result %v", v)
+	}
+}
+
+func TestArrayDecodePointer(t *testing.T) {
+	var item Array[[2]*Uint]
+	if _, err := Decode([]byte{0xc2, 0x01, 0x02}, &item); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v := item.Get(); v[0] == nil || v[1] == nil || *v[0] != 1 || *v[1] != 2 {
+		t.Fatalf("unexpected result %v", v)
+	}
+}
@@ -0,0 +1,224 @@
+// Command rlpdump prints the structure of RLP encoded data as an indented
+// tree.
+//
+// Usage:
+//
+//	rlpdump [flags] [file]
+//
+// The data is read from the given file, or from the standard input if no file
+// is given. The input may be hex encoded (with or without the 0x prefix),
+// base64 encoded or raw binary data. By default, the format is detected
+// automatically: input that consists only of printable ASCII characters and
+// white space is decoded as hex if possible, and then as base64 if possible;
+// any other input is used as raw data. The guess may be wrong, because some
+// data is valid in more than one format, for example raw data that happens to
+// consist of hex digits. Use the -format flag to avoid the ambiguity.
+//
+// In the -single mode, which is the default, the input must contain exactly
+// one RLP item. In the -stream mode, the input may contain any number of
+// concatenated items, which are printed one after another. The two modes are
+// mutually exclusive.
+//
+// Strings are printed as quoted text if they contain only printable ASCII
+// characters, and as hex otherwise. Hex strings that are valid RLP integers
+// of at most 8 bytes are followed by their decimal value.
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/defiweb/go-rlp"
+)
+
+func main() {
+	var (
+		format = flag.String("format", "auto", "input format: auto, hex, base64 or raw")
+		single = flag.Bool("single", false, "expect exactly one item in the input (default)")
+		stream = flag.Bool("stream", false, "print all concatenated items in the input")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if err := checkModes(*single, *stream); err != nil {
+		fatal(err)
+	}
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	in, err := readInput(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	data, err := decodeInput(in, *format)
+	if err != nil {
+		fatal(err)
+	}
+	if err := dump(os.Stdout, data, *stream); err != nil {
+		fatal(err)
+	}
+}
+
+// checkModes verifies that the -single and -stream modes are not both
+// selected.
+func checkModes(single, stream bool) error {
+	if single && stream {
+		return errors.New("the -single and -stream flags are mutually exclusive")
+	}
+	return nil
+}
+
+// readInput reads the whole content of the given file, or of the standard
+// input if the path is empty.
+func readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// decodeInput converts the input in the given format to binary data.
+func decodeInput(in []byte, format string) ([]byte, error) {
+	switch format {
+	case "raw":
+		return in, nil
+	case "hex":
+		return decodeHex(in)
+	case "base64":
+		return decodeBase64(in)
+	case "auto":
+		if !isText(in) {
+			return in, nil
+		}
+		if data, err := decodeHex(in); err == nil {
+			return data, nil
+		}
+		if data, err := decodeBase64(in); err == nil {
+			return data, nil
+		}
+		return in, nil
+	default:
+		return nil, fmt.Errorf("unknown input format: %s", format)
+	}
+}
+
+// decodeHex decodes hex encoded input. White space and the 0x prefix are
+// ignored.
+func decodeHex(in []byte) ([]byte, error) {
+	s := strings.Join(strings.Fields(string(in)), "")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	return hex.DecodeString(s)
+}
+
+// decodeBase64 decodes base64 encoded input. White space is ignored.
+func decodeBase64(in []byte) ([]byte, error) {
+	s := strings.Join(strings.Fields(string(in)), "")
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// dump prints the RLP items in data to w. If stream is false, data must
+// contain exactly one item.
+func dump(w io.Writer, data []byte, stream bool) error {
+	if len(data) == 0 {
+		return errors.New("empty input")
+	}
+	for offset := 0; offset < len(data); {
+		r, n, err := rlp.DecodeLazy(data[offset:])
+		if err != nil {
+			return fmt.Errorf("invalid item at offset %d: %w", offset, err)
+		}
+		if !stream && n != len(data) {
+			return fmt.Errorf("%w after offset %d, use -stream to print all items", rlp.ErrUnexpectedTrailingData, n)
+		}
+		var b bytes.Buffer
+		if err := dumpItem(&b, r, 0); err != nil {
+			return fmt.Errorf("invalid item at offset %d: %w", offset, err)
+		}
+		if _, err := b.WriteTo(w); err != nil {
+			return err
+		}
+		offset += n
+	}
+	return nil
+}
+
+// dumpItem writes a single RLP item to b, indented to the given depth.
+func dumpItem(b *bytes.Buffer, r rlp.RLP, depth int) error {
+	indent := strings.Repeat("  ", depth)
+	if r.IsList() {
+		items, err := r.List()
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			b.WriteString(indent + "[]\n")
+			return nil
+		}
+		b.WriteString(indent + "[\n")
+		for _, item := range items {
+			if err := dumpItem(b, *item, depth+1); err != nil {
+				return err
+			}
+		}
+		b.WriteString(indent + "]\n")
+		return nil
+	}
+	s, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+	b.WriteString(indent + formatString(s, r) + "\n")
+	return nil
+}
+
+// formatString returns the text representation of an RLP string.
+func formatString(s []byte, r rlp.RLP) string {
+	if len(s) == 0 {
+		return `""`
+	}
+	if isPrintable(s) {
+		return strconv.Quote(string(s))
+	}
+	str := "0x" + hex.EncodeToString(s)
+	if u, err := r.Uint(); err == nil {
+		str += " (" + strconv.FormatUint(u.Get(), 10) + ")"
+	}
+	return str
+}
+
+// isPrintable returns true if s contains only printable ASCII characters.
+func isPrintable(s []byte) bool {
+	for _, c := range s {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// isText returns true if s contains only printable ASCII characters and
+// white space.
+func isText(s []byte) bool {
+	for _, c := range s {
+		if (c < 0x20 || c > 0x7e) && !strings.ContainsRune(" \t\r\n", rune(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "rlpdump:", err)
+	os.Exit(1)
+}
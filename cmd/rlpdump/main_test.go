@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/defiweb/go-rlp"
+)
+
+func TestCheckModes(t *testing.T) {
+	if err := checkModes(false, false); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := checkModes(true, false); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := checkModes(false, true); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := checkModes(true, true); err == nil {
+		t.Fatal("expected an error for -single with -stream")
+	}
+}
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		in      string
+		format  string
+		want    []byte
+		wantErr bool
+	}{
+		{in: "c483646f67", format: "hex", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "0xc4 8364\n6f67\n", format: "hex", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "c48", format: "hex", wantErr: true},
+		{in: "xyz", format: "hex", wantErr: true},
+		{in: "xINkb2c=", format: "base64", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "xINk\nb2c=\n", format: "base64", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "xINkb2c", format: "base64", wantErr: true},
+		{in: "\xc4\x83dog", format: "raw", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "c483", format: "raw", want: []byte("c483")},
+		{in: "0xc483646f67\n", format: "auto", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "xINkb2c=", format: "auto", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		{in: "\xc4\x83dog", format: "auto", want: []byte{0xc4, 0x83, 'd', 'o', 'g'}},
+		// Binary data is used as is, even if it would be valid base64 after
+		// the white space is removed.
+		{in: "\x0bAAAA", format: "auto", want: []byte("\x0bAAAA")},
+		{in: "c483", format: "xml", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := decodeInput([]byte(tt.in), tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %x", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		stream  bool
+		want    string
+		wantErr error
+	}{
+		{
+			data: []byte{0xcb, 0x83, 'd', 'o', 'g', 0x82, 0x04, 0x00, 0xc2, 0x01, 0x02, 0x80},
+			want: "[\n  \"dog\"\n  0x0400 (1024)\n  [\n    0x01 (1)\n    0x02 (2)\n  ]\n  \"\"\n]\n",
+		},
+		{
+			data: []byte{0xc0},
+			want: "[]\n",
+		},
+		{
+			data: []byte{0xc3, 0xc0, 0xc1, 0xc0},
+			want: "[\n  []\n  [\n    []\n  ]\n]\n",
+		},
+		{
+			data: []byte{0x82, 0x00, 0x01},
+			want: "0x0001\n",
+		},
+		{
+			data: []byte{0x41},
+			want: "\"A\"\n",
+		},
+		{
+			data:    []byte{0x83, 'd', 'o', 'g', 0x01},
+			wantErr: rlp.ErrUnexpectedTrailingData,
+		},
+		{
+			data:   []byte{0x83, 'd', 'o', 'g', 0x01, 0xc0},
+			stream: true,
+			want:   "\"dog\"\n0x01 (1)\n[]\n",
+		},
+		{
+			data:    []byte{0x83, 'd', 'o', 'g', 0x82, 0x01},
+			stream:  true,
+			wantErr: rlp.ErrUnexpectedEndOfData,
+		},
+		{
+			data:    []byte{0xc2, 0x82, 0x01},
+			wantErr: rlp.ErrUnexpectedEndOfData,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var b bytes.Buffer
+			err := dump(&b, tt.data, tt.stream)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDumpEmpty(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		if err := dump(new(bytes.Buffer), nil, stream); err == nil {
+			t.Fatalf("expected an error for empty input (stream %v)", stream)
+		}
+	}
+}
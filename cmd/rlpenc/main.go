@@ -0,0 +1,280 @@
+// Command rlpenc encodes values written in a simple text notation as RLP.
+//
+// Usage:
+//
+//	rlpenc [flags] [value]
+//
+// If no value is given, it is read from the standard input. The notation
+// consists of:
+//
+//   - quoted strings, for example "dog", encoded as RLP strings; the Go
+//     escape sequences are supported,
+//   - hex literals, for example 0x0400, encoded as RLP strings containing
+//     the given bytes; the prefix may also be written as 0X,
+//   - decimal integers, for example 1024, encoded as RLP integers,
+//   - lists of values in square brackets, separated by commas, for example
+//     [1, [2, 3], "dog"], encoded as RLP lists.
+//
+// With the -json flag, the input is parsed as JSON instead. JSON arrays are
+// encoded as lists, numbers as integers, and strings as RLP strings, except
+// for strings with the 0x or 0X prefix, which are decoded as hex literals.
+// This is a superset of the JSON representation used by rlp.ToJSON and
+// rlp.FromJSON, which accept only lists and 0x-prefixed hex strings, so the
+// output of rlp.ToJSON is encoded back to the same data. Unlike
+// rlp.FromJSON, plain strings and numbers are accepted as well.
+//
+// The encoding is printed as hex by default, or as raw binary data with the
+// -binary flag.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/defiweb/go-rlp"
+)
+
+func main() {
+	var (
+		useJSON = flag.Bool("json", false, "parse the input as JSON")
+		binary  = flag.Bool("binary", false, "print the encoding as raw binary data instead of hex")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [value]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	in := strings.Join(flag.Args(), " ")
+	if flag.NArg() == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fatal(err)
+		}
+		in = string(b)
+	}
+	var (
+		item rlp.Encoder
+		err  error
+	)
+	if *useJSON {
+		item, err = parseJSON(in)
+	} else {
+		item, err = parse(in)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	data, err := rlp.Encode(item)
+	if err != nil {
+		fatal(err)
+	}
+	if *binary {
+		_, err = os.Stdout.Write(data)
+	} else {
+		_, err = fmt.Println(hex.EncodeToString(data))
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// parser parses the text notation.
+type parser struct {
+	in  string
+	pos int
+}
+
+// parse parses a single value in the text notation.
+func parse(in string) (rlp.Encoder, error) {
+	p := &parser{in: in}
+	item, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.in) {
+		return nil, p.errorf("unexpected %q after the value", p.in[p.pos])
+	}
+	return item, nil
+}
+
+// value parses the value at the current position.
+func (p *parser) value() (rlp.Encoder, error) {
+	p.skipSpace()
+	if p.pos >= len(p.in) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch c := p.in[p.pos]; {
+	case c == '[':
+		return p.list()
+	case c == '"':
+		return p.string()
+	case c >= '0' && c <= '9':
+		return p.number()
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+// list parses a list in square brackets.
+func (p *parser) list() (rlp.Encoder, error) {
+	p.pos++ // Skip the opening bracket.
+	list := rlp.List{}
+	p.skipSpace()
+	if p.consume(']') {
+		return list, nil
+	}
+	for {
+		item, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+		p.skipSpace()
+		if p.consume(']') {
+			return list, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+// string parses a quoted string.
+func (p *parser) string() (rlp.Encoder, error) {
+	end := p.pos + 1
+	for ; end < len(p.in) && p.in[end] != '"'; end++ {
+		if p.in[end] == '\\' {
+			end++ // Skip the escaped character.
+		}
+	}
+	if end >= len(p.in) {
+		return nil, p.errorf("unterminated string")
+	}
+	s, err := strconv.Unquote(p.in[p.pos : end+1])
+	if err != nil {
+		return nil, p.errorf("invalid string: %v", err)
+	}
+	p.pos = end + 1
+	return rlp.String(s), nil
+}
+
+// number parses a hex literal or a decimal integer.
+func (p *parser) number() (rlp.Encoder, error) {
+	end := p.pos
+	for end < len(p.in) && isAlnum(p.in[end]) {
+		end++
+	}
+	lit := p.in[p.pos:end]
+	item, err := parseNumber(lit)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	p.pos = end
+	return item, nil
+}
+
+// skipSpace advances the position past any white space.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.in) && strings.ContainsRune(" \t\r\n", rune(p.in[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume advances the position past the given character if it is the next
+// character in the input.
+func (p *parser) consume(c byte) bool {
+	if p.pos < len(p.in) && p.in[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// errorf returns an error for the current position.
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseJSON parses a single JSON value.
+func parseJSON(in string) (rlp.Encoder, error) {
+	dec := json.NewDecoder(strings.NewReader(in))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return fromJSON(v)
+}
+
+// fromJSON converts a value decoded from JSON to an RLP item.
+func fromJSON(v any) (rlp.Encoder, error) {
+	switch v := v.(type) {
+	case []any:
+		list := make(rlp.List, 0, len(v))
+		for _, item := range v {
+			enc, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, enc)
+		}
+		return list, nil
+	case string:
+		if hasHexPrefix(v) {
+			return parseNumber(v)
+		}
+		return rlp.String(v), nil
+	case json.Number:
+		return parseNumber(v.String())
+	default:
+		return nil, fmt.Errorf("unsupported JSON value: %v", v)
+	}
+}
+
+// parseNumber parses a hex literal, which is converted to bytes, or a
+// decimal integer.
+func parseNumber(lit string) (rlp.Encoder, error) {
+	if hasHexPrefix(lit) {
+		b, err := hex.DecodeString(lit[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex literal %q", lit)
+		}
+		return rlp.Bytes(b), nil
+	}
+	n, ok := new(big.Int).SetString(lit, 10)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("invalid integer %q", lit)
+	}
+	if n.IsUint64() {
+		return rlp.Uint(n.Uint64()), nil
+	}
+	return (*rlp.BigInt)(n), nil
+}
+
+// hasHexPrefix returns true if s starts with the 0x or 0X prefix, which
+// are both accepted, as in rlpdump.
+func hasHexPrefix(s string) bool {
+	return strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+}
+
+// isAlnum returns true if c is an ASCII letter or digit.
+func isAlnum(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "rlpenc:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/defiweb/go-rlp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		// Example from the README.
+		{in: `["dog", 0x0400, [1, 2], ""]`, want: "cb83646f67820400c2010280"},
+		// Strings.
+		{in: `""`, want: "80"},
+		{in: `"dog"`, want: "83646f67"},
+		{in: `"a\nb\x00é"`, want: "86610a6200c3a9"},
+		{in: `"say \"hi\""`, want: "887361792022686922"},
+		{in: `"dog`, wantErr: true},
+		{in: `"dog\"`, wantErr: true},
+		{in: `"\q"`, wantErr: true},
+		// Hex literals.
+		{in: `0x`, want: "80"},
+		{in: `0x00`, want: "00"},
+		{in: `0xff`, want: "81ff"},
+		{in: `0XFF`, want: "81ff"},
+		{in: `0x0`, wantErr: true},
+		{in: `0x123`, wantErr: true},
+		{in: `0xzz`, wantErr: true},
+		// Integers.
+		{in: `0`, want: "80"},
+		{in: `127`, want: "7f"},
+		{in: `1024`, want: "820400"},
+		{in: `18446744073709551615`, want: "88ffffffffffffffff"},
+		{in: `18446744073709551616`, want: "89010000000000000000"},
+		{in: `1a`, wantErr: true},
+		{in: `-1`, wantErr: true},
+		// Lists.
+		{in: `[]`, want: "c0"},
+		{in: ` [ [] , [[]] ] `, want: "c3c0c1c0"},
+		{in: `[1, [2, [3]]]`, want: "c501c302c103"},
+		{in: `[1, 2`, wantErr: true},
+		{in: `[1,]`, wantErr: true},
+		{in: `[1 2]`, wantErr: true},
+		// Trailing data and empty input.
+		{in: `1 2`, wantErr: true},
+		{in: `[] ]`, wantErr: true},
+		{in: ``, wantErr: true},
+		{in: `  `, wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			item, err := parse(tt.in)
+			checkEncoding(t, item, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `["dog", "0x0400", [1, 2], ""]`, want: "cb83646f67820400c2010280"},
+		{in: `"0x"`, want: "80"},
+		{in: `"0xff"`, want: "81ff"},
+		{in: `"0XFF"`, want: "81ff"},
+		{in: `"0x0"`, wantErr: true},
+		{in: `"dog"`, want: "83646f67"},
+		{in: `[]`, want: "c0"},
+		{in: `[[], [[]]]`, want: "c3c0c1c0"},
+		{in: `0`, want: "80"},
+		{in: `1024`, want: "820400"},
+		{in: `18446744073709551616`, want: "89010000000000000000"},
+		{in: `1.5`, wantErr: true},
+		{in: `1e3`, wantErr: true},
+		{in: `-1`, wantErr: true},
+		{in: `true`, wantErr: true},
+		{in: `null`, wantErr: true},
+		{in: `{"a": 1}`, wantErr: true},
+		{in: `[1, 2`, wantErr: true},
+		{in: `1 2`, wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			item, err := parseJSON(tt.in)
+			checkEncoding(t, item, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParseJSONFromToJSON(t *testing.T) {
+	data := []byte{0xcb, 0x83, 'd', 'o', 'g', 0x80, 0xc4, 0x01, 0x82, 0x04, 0x00, 0xc0}
+	js, err := rlp.ToJSON(data)
+	if err != nil {
+		t.Fatalf("ToJSON() failed: %v", err)
+	}
+	item, err := parseJSON(string(js))
+	checkEncoding(t, item, err, hex.EncodeToString(data), false)
+}
+
+// checkEncoding verifies that the parsed item is encoded as the given hex
+// string, or that parsing failed if wantErr is true.
+func checkEncoding(t *testing.T, item rlp.Encoder, err error, want string, wantErr bool) {
+	t.Helper()
+	if wantErr {
+		if err == nil {
+			t.Fatalf("expected an error, got item %v", item)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data, err := rlp.Encode(item)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error %v", err)
+	}
+	if got := hex.EncodeToString(data); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,102 @@
+package rlp
+
+import (
+	"io"
+)
+
+// StreamEncoder writes RLP encoded items to an io.Writer.
+//
+// Unlike the Encode function, the StreamEncoder does not build the encoding
+// of lists in memory. Instead, it first computes the payload sizes of all
+// nested lists, and then writes the list prefixes and items directly to the
+// writer. This makes it possible to encode large data structures without
+// holding their encoding in memory.
+//
+// Items of the List, TypedList, VarList and VarTypedList types are streamed
+// one by one. Other items are encoded into an internal buffer, which is reused
+// for subsequent items, and then written to the writer.
+//
+// The StreamEncoder writes many small chunks of data, so the writer should be
+// buffered, for example using bufio.Writer.
+type StreamEncoder struct {
+	w     io.Writer
+	sizes []int // Payload sizes of the lists, in the order of encoding.
+	pos   int   // Index of the next list size to use.
+	buf   []byte
+}
+
+// NewEncoder returns a new StreamEncoder that writes to w.
+func NewEncoder(w io.Writer) *StreamEncoder {
+	return &StreamEncoder{w: w}
+}
+
+// Encode writes the RLP encoding of v to the underlying writer.
+//
+// All items are measured before anything is written, so errors such as nil
+// items or unsupported item types are returned before writing to the writer.
+//
+// If v is nil, ErrNilValue is returned.
+func (e *StreamEncoder) Encode(v Encoder) error {
+	if isNil(v) {
+		return ErrNilValue
+	}
+	e.sizes = e.sizes[:0]
+	e.pos = 0
+	if _, err := e.measure(v); err != nil {
+		return err
+	}
+	return e.write(v)
+}
+
+// measure returns the length of the RLP encoding of the given item and
+// records the payload sizes of the lists it contains.
+func (e *StreamEncoder) measure(item any) (int, error) {
+	if isNil(item) {
+		return 0, ErrNilValue
+	}
+	l, ok := item.(itemLister)
+	if !ok {
+		return encodedSize(item)
+	}
+	// The payload size is known only after all items are measured, so the
+	// slot is reserved before measuring the items.
+	idx := len(e.sizes)
+	e.sizes = append(e.sizes, 0)
+	payload := 0
+	err := l.eachItem(func(item any) error {
+		n, err := e.measure(item)
+		payload += n
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	e.sizes[idx] = payload
+	return prefixSize(uint64(payload)) + payload, nil
+}
+
+// write writes the RLP encoding of the given item using the list sizes
+// recorded by measure.
+func (e *StreamEncoder) write(item any) error {
+	l, ok := item.(itemLister)
+	if !ok {
+		buf, err := appendItem(e.buf[:0], item)
+		if err != nil {
+			return err
+		}
+		e.buf = buf
+		_, err = e.w.Write(buf)
+		return err
+	}
+	payload := e.sizes[e.pos]
+	e.pos++
+	buf, err := appendPrefix(e.buf[:0], uint64(payload), listOffset)
+	if err != nil {
+		return err
+	}
+	e.buf = buf
+	if _, err := e.w.Write(buf); err != nil {
+		return err
+	}
+	return l.eachItem(e.write)
+}
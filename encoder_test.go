@@ -0,0 +1,95 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestStreamEncoder(t *testing.T) {
+	tests := []struct {
+		data    Encoder
+		wantErr error
+	}{
+		{data: String("")},
+		{data: String("dog")},
+		{data: String(strings.Repeat("a", 56))},
+		{data: Bytes{0x01}},
+		{data: Uint(0)},
+		{data: Uint(1024)},
+		{data: (*BigInt)(big.NewInt(1024))},
+		{data: RLP{0xc1, 0x80}},
+		{data: List{}},
+		{data: List{String("dog"), String("cat")}},
+		{data: List(makeSlice(56, String("a")))},
+		{data: List(makeSlice(256, String("a")))},
+		{data: List{List{String("dog"), List{Uint(1), ptr(Uint(2))}}, String("horse"), List{}}},
+		{data: TypedList[String]{ptr(String("dog")), ptr(String("cat"))}},
+		{data: VarList{String("a"), VarTypedList[Uint]{ptr(Uint(1))}}},
+		{data: testList{String("a"), List{String("b")}}},
+		{data: (*Uint)(nil), wantErr: ErrNilValue},
+		{data: List{nil}, wantErr: ErrNilValue},
+		{data: List{List{(*Uint)(nil)}}, wantErr: ErrNilValue},
+		{data: TypedList[Uint]{nil}, wantErr: ErrNilValue},
+		{data: List{1}, wantErr: ErrUnsupportedType},
+		{data: List{RLP{0x81}}, wantErr: ErrUnexpectedEndOfData},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewEncoder(&buf).Encode(tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Encode() error = %v, want %v", err, tt.wantErr)
+				}
+				if buf.Len() != 0 {
+					t.Fatalf("Encode() wrote %x, want nothing", buf.Bytes())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode() unexpected error = %v", err)
+			}
+			want, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("Encode() unexpected error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Fatalf("Encode() got = %x, want %x", buf.Bytes(), want)
+			}
+		})
+	}
+}
+
+func TestStreamEncoderMultipleItems(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(List{String("foo"), List{}}); err != nil {
+		t.Fatalf("Encode() failed: %v", err)
+	}
+	if err := enc.Encode(List{String("bar")}); err != nil {
+		t.Fatalf("Encode() failed: %v", err)
+	}
+	want := []byte{0xc5, 0x83, 'f', 'o', 'o', 0xc0, 0xc4, 0x83, 'b', 'a', 'r'}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("expected %x, got %x", want, buf.Bytes())
+	}
+}
+
+func TestStreamEncoderWriteError(t *testing.T) {
+	err := NewEncoder(errWriter{}).Encode(List{String("foo")})
+	if !errors.Is(err, errWrite) {
+		t.Fatalf("expected errWrite, got %v", err)
+	}
+}
+
+var errWrite = errors.New("write error")
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
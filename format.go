@@ -0,0 +1,115 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Format implements the fmt.Formatter interface.
+//
+// The %x and %X verbs print the RLP encoding as hex, with the 0x prefix if
+// the # flag is used. The %v and %s verbs print the item as a compact tree,
+// in which strings are printed as hex and lists in square brackets, for
+// example [0x646f67, [0x01, 0x]]. The %+v verb annotates each item with its
+// kind and payload length, for example list(7)[string(3)0x646f67, ...].
+// Single byte strings, which are their own encoding, are annotated as byte.
+//
+// Invalid data is printed as hex with the error.
+func (r RLP) Format(f fmt.State, verb rune) {
+	formatRLP(f, verb, r, r, nil)
+}
+
+// Format implements the fmt.Formatter interface.
+//
+// The list is formatted in the same way as its RLP encoding by the Format
+// method of the RLP type.
+func (l List) Format(f fmt.State, verb rune) {
+	data, err := Encode(l)
+	formatRLP(f, verb, l, data, err)
+}
+
+// Format implements the fmt.Formatter interface.
+//
+// The list is formatted in the same way as its RLP encoding by the Format
+// method of the RLP type.
+func (l VarList) Format(f fmt.State, verb rune) {
+	data, err := Encode(l)
+	formatRLP(f, verb, l, data, err)
+}
+
+// formatRLP writes the RLP data to f according to the verb. The v is the
+// formatted value, whose type is printed for unsupported verbs, and err is an
+// error that occurred while encoding the data, if any.
+func formatRLP(f fmt.State, verb rune, v any, data []byte, err error) {
+	if err != nil {
+		fmt.Fprintf(f, "%%!%c(%v)", verb, err)
+		return
+	}
+	var buf []byte
+	switch verb {
+	case 'x', 'X':
+		if f.Flag('#') {
+			buf = append(buf, "0x"...)
+		}
+		buf = append(buf, hex.EncodeToString(data)...)
+		if verb == 'X' {
+			buf = bytes.ToUpper(buf)
+		}
+	case 'v', 's':
+		if err := Validate(data, DecodeOptions{}); err != nil {
+			buf = append(buf, "invalid(0x"...)
+			buf = append(buf, hex.EncodeToString(data)...)
+			buf = append(buf, ": "...)
+			buf = append(buf, err.Error()...)
+			buf = append(buf, ')')
+			break
+		}
+		buf = appendTree(buf, data, verb == 'v' && f.Flag('+'))
+	default:
+		fmt.Fprintf(f, "%%!%c(%T=%x)", verb, v, data)
+		return
+	}
+	_, _ = f.Write(buf)
+}
+
+// appendTree appends the tree representation of a valid RLP item to dst. If
+// annotate is true, each item is preceded by its kind and payload length.
+func appendTree(dst []byte, r RLP, annotate bool) []byte {
+	if r.IsList() {
+		it := r.Iter()
+		if annotate {
+			dst = append(dst, "list("...)
+			dst = strconv.AppendInt(dst, int64(len(r)-prefixLenOf(r)), 10)
+			dst = append(dst, ')')
+		}
+		dst = append(dst, '[')
+		for i := 0; it.Next(); i++ {
+			if i > 0 {
+				dst = append(dst, ", "...)
+			}
+			dst = appendTree(dst, it.Value(), annotate)
+		}
+		return append(dst, ']')
+	}
+	var b []byte
+	_, _ = readString(r, &b, nil)
+	if annotate {
+		if len(r) == 1 && r[0] <= singleByteMax {
+			dst = append(dst, "byte"...)
+		} else {
+			dst = append(dst, "string("...)
+			dst = strconv.AppendInt(dst, int64(len(b)), 10)
+			dst = append(dst, ')')
+		}
+	}
+	dst = append(dst, "0x"...)
+	return append(dst, hex.EncodeToString(b)...)
+}
+
+// prefixLenOf returns the length of the prefix of a valid RLP item.
+func prefixLenOf(r RLP) int {
+	_, _, prefixLen, _ := decodePrefix(r)
+	return int(prefixLen)
+}
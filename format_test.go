@@ -0,0 +1,42 @@
+package rlp
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		value  any
+		want   string
+	}{
+		{"%x", RLP{0xc2, 0x01, 0x80}, "c20180"},
+		{"%#x", RLP{0xc2, 0x01, 0x80}, "0xc20180"},
+		{"%X", RLP{0x83, 'd', 'o', 'g'}, "83646F67"},
+		{"%#X", RLP{0x83, 'd', 'o', 'g'}, "0X83646F67"},
+		{"%v", RLP{0x80}, "0x"},
+		{"%v", RLP{0x01}, "0x01"},
+		{"%v", RLP{0xc0}, "[]"},
+		{"%v", RLP{0xc7, 0x83, 'd', 'o', 'g', 0xc1, 0x01, 0x80}, "[0x646f67, [0x01], 0x]"},
+		{"%s", RLP{0xc7, 0x83, 'd', 'o', 'g', 0xc1, 0x01, 0x80}, "[0x646f67, [0x01], 0x]"},
+		{"%+v", RLP{0xc7, 0x83, 'd', 'o', 'g', 0xc1, 0x01, 0x80}, "list(7)[string(3)0x646f67, list(1)[byte0x01], string(0)0x]"},
+		{"%+v", RLP{0x81, 0x80}, "string(1)0x80"},
+		{"%v", RLP{0xc2, 0x01}, "invalid(0xc201: rlp: unexpected end of data (offset 0))"},
+		{"%v", RLP{0x80, 0x80}, "invalid(0x8080: rlp: unexpected trailing data (offset 1))"},
+		{"%d", RLP{0x80}, "%!d(rlp.RLP=80)"},
+		{"%d", List{String("a")}, "%!d(rlp.List=c161)"},
+		{"%q", VarList{String("a")}, "%!q(rlp.VarList=c161)"},
+		{"%v", List{String("dog"), List{Uint(1)}}, "[0x646f67, [0x01]]"},
+		{"%x", List{String("dog"), List{Uint(1)}}, "c683646f67c101"},
+		{"%v", VarList{Uint(1024)}, "[0x0400]"},
+		{"%v", List{nil}, "%!v(rlp: nil value)"},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.value); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
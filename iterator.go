@@ -0,0 +1,69 @@
+package rlp
+
+// Iterator iterates over the items of an RLP list without decoding them.
+//
+// The items are returned as RLP values that share memory with the list, so
+// the iteration does not allocate memory.
+//
+// Example:
+//
+//	it := r.Iter()
+//	for it.Next() {
+//		item := it.Value()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type Iterator struct {
+	data  []byte // Remaining list payload.
+	pos   int    // Offset of the remaining payload in the list.
+	index int    // Index of the next item.
+	value RLP    // Current item.
+	err   error
+}
+
+// Iter returns an Iterator over the items of the list. If the RLP data is not
+// a valid list, the returned Iterator yields no items and its Err method
+// returns the error.
+func (r RLP) Iter() Iterator {
+	data, totalLen, err := decodeListPayload(r, nil)
+	if err != nil {
+		return Iterator{err: decodeError(err, rlpType)}
+	}
+	return Iterator{data: data, pos: totalLen - len(data)}
+}
+
+// Next advances the iterator to the next item. It returns false if there are
+// no more items or an invalid item is found.
+func (it *Iterator) Next() bool {
+	it.value = nil
+	if it.err != nil || len(it.data) == 0 {
+		return false
+	}
+	_, dataLen, prefixLen, err := decodePrefix(it.data)
+	if err != nil {
+		it.err = itemError(err, it.index, it.pos, nil)
+		return false
+	}
+	itemLen := int(dataLen + uint64(prefixLen))
+	if itemLen > len(it.data) {
+		it.err = itemError(ErrUnexpectedEndOfData, it.index, it.pos, nil)
+		return false
+	}
+	it.value = RLP(it.data[:itemLen])
+	it.data = it.data[itemLen:]
+	it.pos += itemLen
+	it.index++
+	return true
+}
+
+// Value returns the current item. It is valid only after Next returns true.
+func (it *Iterator) Value() RLP {
+	return it.value
+}
+
+// Err returns the error found during the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
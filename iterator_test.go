@@ -0,0 +1,69 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		want     []RLP
+		wantErr  error
+		wantPath string
+	}{
+		{data: []byte{0xc0}, want: nil},
+		{data: []byte{0xc2, 0x01, 0x02}, want: []RLP{{0x01}, {0x02}}},
+		{data: []byte{0xc5, 0x83, 'd', 'o', 'g', 0xc0}, want: []RLP{{0x83, 'd', 'o', 'g'}, {0xc0}}},
+		{data: []byte{0x80}, wantErr: ErrUnsupportedType},
+		{data: []byte{0xc3, 0x01}, wantErr: ErrUnexpectedEndOfData},
+		{data: []byte{0xc2, 0x01, 0x82}, want: []RLP{{0x01}}, wantErr: ErrUnexpectedEndOfData, wantPath: "[1]"},
+		{data: []byte{0xc3, 0x01, 0x81, 0x01}, want: []RLP{{0x01}}, wantErr: ErrNonCanonicalEncoding, wantPath: "[1]"},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var got []RLP
+			it := RLP(tt.data).Iter()
+			for it.Next() {
+				got = append(got, it.Value())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d items, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !bytes.Equal(got[i], tt.want[i]) {
+					t.Errorf("item %d = %x, want %x", i, got[i], tt.want[i])
+				}
+			}
+			if !errors.Is(it.Err(), tt.wantErr) {
+				t.Fatalf("Err() = %v, want %v", it.Err(), tt.wantErr)
+			}
+			var decErr *DecodeError
+			if errors.As(it.Err(), &decErr) && decErr.PathString() != tt.wantPath {
+				t.Fatalf("Err() path = %q, want %q", decErr.PathString(), tt.wantPath)
+			}
+			if it.Next() {
+				t.Fatal("Next() returned true after the end of the iteration")
+			}
+		})
+	}
+}
+
+func TestIteratorAllocs(t *testing.T) {
+	data, err := Encode(VarTypedList[Uint]{ptr(Uint(1)), ptr(Uint(1000)), ptr(Uint(1000000))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := RLP(data)
+	allocs := testing.AllocsPerRun(100, func() {
+		it := r.Iter()
+		for it.Next() {
+			_ = it.Value()
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,116 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidJSON = errors.New("rlp: invalid JSON representation")
+
+// ToJSON converts RLP encoded data to its JSON representation, in which
+// strings are represented as 0x-prefixed hex strings and lists as JSON
+// arrays. For example, the encoding of ["dog", [1]] is represented as
+// ["0x646f67",["0x01"]].
+//
+// The data must contain exactly one canonically encoded RLP item.
+func ToJSON(data []byte) ([]byte, error) {
+	if err := Validate(data, DecodeOptions{}); err != nil {
+		return nil, err
+	}
+	return appendJSON(nil, data)
+}
+
+// FromJSON converts the JSON representation returned by ToJSON back to RLP
+// encoded data.
+func FromJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	return appendFromJSON(nil, v)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+//
+// The RLP item is represented in the same way as by the ToJSON function. Nil
+// RLP is represented as null.
+func (r RLP) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+	return ToJSON(r)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *RLP) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*r = nil
+		return nil
+	}
+	b, err := FromJSON(data)
+	if err != nil {
+		return err
+	}
+	*r = b
+	return nil
+}
+
+// appendJSON appends the JSON representation of the RLP item to dst. The
+// item must be already validated.
+func appendJSON(dst []byte, r RLP) ([]byte, error) {
+	if r.IsList() {
+		dst = append(dst, '[')
+		it := r.Iter()
+		for i := 0; it.Next(); i++ {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var err error
+			if dst, err = appendJSON(dst, it.Value()); err != nil {
+				return nil, err
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+		return append(dst, ']'), nil
+	}
+	var b []byte
+	if _, err := readString(r, &b, nil); err != nil {
+		return nil, err
+	}
+	dst = append(dst, `"0x`...)
+	dst = append(dst, hex.EncodeToString(b)...)
+	return append(dst, '"'), nil
+}
+
+// appendFromJSON appends the RLP encoding of a value decoded from its JSON
+// representation to dst.
+func appendFromJSON(dst []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case []any:
+		start := len(dst)
+		dst = append(dst, 0)
+		for _, item := range v {
+			var err error
+			if dst, err = appendFromJSON(dst, item); err != nil {
+				return nil, err
+			}
+		}
+		return finishList(dst, start)
+	case string:
+		if len(v) < 2 || v[:2] != "0x" {
+			return nil, fmt.Errorf("%w: string %q has no 0x prefix", ErrInvalidJSON, v)
+		}
+		b, err := hex.DecodeString(v[2:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid hex string %q", ErrInvalidJSON, v)
+		}
+		return appendBytes(dst, b)
+	default:
+		return nil, fmt.Errorf("%w: unexpected value %v", ErrInvalidJSON, v)
+	}
+}
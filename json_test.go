@@ -0,0 +1,99 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    string
+		wantErr error
+	}{
+		{data: []byte{0x80}, want: `"0x"`},
+		{data: []byte{0x01}, want: `"0x01"`},
+		{data: []byte{0x83, 'd', 'o', 'g'}, want: `"0x646f67"`},
+		{data: []byte{0xc0}, want: `[]`},
+		{data: []byte{0xc7, 0x83, 'd', 'o', 'g', 0xc1, 0x01, 0x80}, want: `["0x646f67",["0x01"],"0x"]`},
+		{data: []byte{0xc1, 0xc0}, want: `[[]]`},
+		{data: []byte{0x80, 0x80}, wantErr: ErrUnexpectedTrailingData},
+		{data: []byte{0x81, 0x01}, wantErr: ErrNonCanonicalEncoding},
+		{data: []byte{0xc2, 0x01}, wantErr: ErrUnexpectedEndOfData},
+		{data: []byte{}, wantErr: ErrUnexpectedEndOfData},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := ToJSON(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+			// The JSON representation must round-trip exactly.
+			back, err := FromJSON(got)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(back, tt.data) {
+				t.Fatalf("expected %x, got %x", tt.data, back)
+			}
+		})
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    []byte
+		wantErr error
+	}{
+		{json: ` [ "0x646f67" , [ ] ] `, want: []byte{0xc5, 0x83, 'd', 'o', 'g', 0xc0}},
+		{json: `"0x00"`, want: []byte{0x00}},
+		{json: `"dog"`, wantErr: ErrInvalidJSON},
+		{json: `"0xzz"`, wantErr: ErrInvalidJSON},
+		{json: `1`, wantErr: ErrInvalidJSON},
+		{json: `[null]`, wantErr: ErrInvalidJSON},
+		{json: `[`, wantErr: ErrInvalidJSON},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := FromJSON([]byte(tt.json))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRLPJSON(t *testing.T) {
+	type fixture struct {
+		Payload RLP
+		Empty   RLP
+	}
+	in := fixture{Payload: RLP{0xc4, 0x83, 'c', 'a', 't'}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := `{"Payload":["0x636174"],"Empty":null}`; string(data) != want {
+		t.Fatalf("expected %s, got %s", want, data)
+	}
+	var out fixture
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !bytes.Equal(out.Payload, in.Payload) || out.Empty != nil {
+		t.Fatalf("expected %v, got %v", in, out)
+	}
+}
@@ -0,0 +1,185 @@
+package rlp
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+)
+
+// Map is a map type that can be encoded and decoded to/from RLP.
+//
+// The map is encoded as an RLP list of [key, value] pairs, sorted by the RLP
+// encoding of the keys, so that the encoding of the map is deterministic.
+//
+// The K and V types, or pointers to them, must implement the Encoder
+// interface if the map is being encoded. The *K and *V types must implement
+// the Decoder interface if the map is being decoded, unless K or V are
+// pointer types that implement it, like *Uint.
+//
+// During decoding, the keys must be sorted, otherwise ErrNonCanonicalEncoding
+// is returned, and must be unique, otherwise ErrDuplicateKey is returned. In
+// the lenient mode enabled by DecodeOptions.AllowNonCanonical, both are
+// accepted and reported as non-canonical encodings; for duplicate keys, the
+// last value is used.
+type Map[K comparable, V any] map[K]V
+
+// Get returns the map.
+func (m Map[K, V]) Get() map[K]V {
+	return m
+}
+
+// Ptr returns a pointer to the map.
+func (m *Map[K, V]) Ptr() *map[K]V {
+	return (*map[K]V)(m)
+}
+
+// Set sets the map.
+func (m *Map[K, V]) Set(value map[K]V) {
+	*m = value
+}
+
+// EncodeRLP implements the Encoder interface.
+func (m Map[K, V]) EncodeRLP() ([]byte, error) {
+	return m.AppendRLP(nil)
+}
+
+// AppendRLP implements the Appender interface.
+func (m Map[K, V]) AppendRLP(dst []byte) ([]byte, error) {
+	pairs := make([]mapPair[V], 0, len(m))
+	for k, v := range m {
+		key, err := appendItem(nil, encoderOf(k))
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, mapPair[V]{key: key, value: v})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return bytes.Compare(pairs[i].key, pairs[j].key) < 0
+	})
+	start := len(dst)
+	dst = append(dst, 0)
+	for i, p := range pairs {
+		if i > 0 && bytes.Equal(pairs[i-1].key, p.key) {
+			// Different keys with the same encoding cannot be decoded.
+			return nil, ErrDuplicateKey
+		}
+		pairStart := len(dst)
+		dst = append(dst, 0)
+		dst = append(dst, p.key...)
+		var err error
+		if dst, err = appendItem(dst, encoderOf(p.value)); err != nil {
+			return nil, err
+		}
+		if dst, err = finishList(dst, pairStart); err != nil {
+			return nil, err
+		}
+	}
+	return finishList(dst, start)
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the map cannot be encoded, it returns 0. The size is computed from the
+// sizes of the keys and values, without encoding them, so different keys
+// with the same encoding are not detected; they are reported by AppendRLP.
+func (m Map[K, V]) EncodedSize() int {
+	size := 0
+	for k, v := range m {
+		keySize, err := encodedSize(encoderOf(k))
+		if err != nil {
+			return 0
+		}
+		valueSize, err := encodedSize(encoderOf(v))
+		if err != nil {
+			return 0
+		}
+		payload := keySize + valueSize
+		size += prefixSize(uint64(payload)) + payload
+	}
+	return prefixSize(uint64(size)) + size
+}
+
+// DecodeRLP implements the Decoder interface.
+func (m *Map[K, V]) DecodeRLP(data []byte) (int, error) {
+	return m.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (m *Map[K, V]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	typ := reflect.TypeOf(map[K]V(nil))
+	payload, totalLen, err := decodeListPayload(data, s)
+	if err != nil {
+		return 0, decodeError(err, typ)
+	}
+	var (
+		res  = make(map[K]V)
+		prev RLP
+		pos  = totalLen - len(payload)
+	)
+	for i := 0; pos < totalLen; i++ {
+		item := data[pos:totalLen]
+		var (
+			pair Tuple2[RLP, RLP]
+			k    K
+			v    V
+		)
+		s.enter(i)
+		n, err := decodeItem(&pair, item, s)
+		if err == nil {
+			err = m.verifyOrder(prev, pair.V1, item, s)
+		}
+		if err == nil {
+			// Offset of the key in the pair.
+			keyPos := n - len(pair.V1) - len(pair.V2)
+			if err = decodeMapEntry(decoderOf(&k), pair.V1, 0, s); err != nil {
+				err = itemError(err, 0, keyPos, reflect.TypeOf(k))
+			} else if err = decodeMapEntry(decoderOf(&v), pair.V2, 1, s); err != nil {
+				err = itemError(err, 1, keyPos+len(pair.V1), reflect.TypeOf(v))
+			}
+		}
+		s.leave()
+		if err != nil {
+			return 0, itemError(err, i, pos, nil)
+		}
+		res[k] = v
+		prev = pair.V1
+		pos += n
+	}
+	*m = res
+	return totalLen, nil
+}
+
+// mapPair is a map entry whose key is already encoded, used to sort the
+// entries by the encoding of their keys.
+type mapPair[V any] struct {
+	key   []byte
+	value V
+}
+
+// verifyOrder verifies that the key of the given item is greater than the
+// key of the previous item.
+func (m *Map[K, V]) verifyOrder(prev, key RLP, item []byte, s *decodeState) error {
+	if prev == nil {
+		return nil
+	}
+	switch c := bytes.Compare(prev, key); {
+	case c == 0 && !s.lenient():
+		return ErrDuplicateKey
+	case c >= 0:
+		return s.nonCanonical(item, reflect.TypeOf(map[K]V(nil)))
+	}
+	return nil
+}
+
+// decodeMapEntry decodes the raw key or value of a map entry, which is the
+// item at the given index of the pair, into dst.
+func decodeMapEntry(dst any, data RLP, index int, s *decodeState) error {
+	dec, ok := dst.(Decoder)
+	if !ok {
+		return ErrUnsupportedType
+	}
+	s.enter(index)
+	_, err := decodeItem(dec, data, s)
+	s.leave()
+	return err
+}
@@ -0,0 +1,142 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMapEncode(t *testing.T) {
+	tests := []struct {
+		data    Encoder
+		want    []byte
+		wantErr error
+	}{
+		{data: Map[String, Uint]{}, want: []byte{0xc0}},
+		{data: Map[String, Uint](nil), want: []byte{0xc0}},
+		{
+			data: Map[String, Uint]{"b": 2, "a": 1, "cat": 3},
+			want: []byte{0xcc, 0xc2, 'a', 0x01, 0xc2, 'b', 0x02, 0xc5, 0x83, 'c', 'a', 't', 0x03},
+		},
+		{
+			// Keys are sorted by their encoding, so 0x80 (zero) goes after 0x7f.
+			data: Map[Uint, Bool]{0: true, 127: false, 1: true},
+			want: []byte{0xc9, 0xc2, 0x01, 0x01, 0xc2, 0x7f, 0x80, 0xc2, 0x80, 0x01},
+		},
+		{data: Map[String, *Uint]{"a": nil}, wantErr: ErrNilValue},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			// The encoding must not depend on the map iteration order.
+			for i := 0; i < 10; i++ {
+				got, err := Encode(tt.data)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if !bytes.Equal(got, tt.want) {
+					t.Fatalf("expected %x, got %x", tt.want, got)
+				}
+			}
+			if size := tt.data.(Sizer).EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestMapEncodedSize(t *testing.T) {
+	// Pairs and the map itself are longer than 55 bytes, so they use long
+	// list prefixes.
+	m := Map[Uint, String]{}
+	for i := 0; i < 100; i++ {
+		m[Uint(i)] = String(strings.Repeat("x", i))
+	}
+	data, err := Encode(m)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if size := m.EncodedSize(); size != len(data) {
+		t.Fatalf("expected size %d, got %d", len(data), size)
+	}
+}
+
+func TestMapDecodePointer(t *testing.T) {
+	var m Map[String, *Uint]
+	if _, err := Decode([]byte{0xc6, 0xc2, 'a', 0x01, 0xc2, 'b', 0x02}, &m); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(m) != 2 || m["a"] == nil || *m["a"] != 1 || m["b"] == nil || *m["b"] != 2 {
+		t.Fatalf("unexpected result %v", m)
+	}
+	if m["a"] == m["b"] {
+		t.Fatal("expected distinct values")
+	}
+}
+
+func TestMapDecode(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		opts     DecodeOptions
+		want     map[String]Uint
+		wantErr  error
+		wantPath string
+	}{
+		{data: []byte{0xc0}, want: map[String]Uint{}},
+		{
+			data: []byte{0xc6, 0xc2, 'a', 0x01, 0xc2, 'b', 0x02},
+			want: map[String]Uint{"a": 1, "b": 2},
+		},
+		{
+			data:    []byte{0xc6, 0xc2, 'b', 0x02, 0xc2, 'a', 0x01},
+			wantErr: ErrNonCanonicalEncoding, wantPath: "[1]",
+		},
+		{
+			data:    []byte{0xc6, 0xc2, 'a', 0x01, 0xc2, 'a', 0x02},
+			wantErr: ErrDuplicateKey, wantPath: "[1]",
+		},
+		{
+			data: []byte{0xc6, 0xc2, 'b', 0x02, 0xc2, 'a', 0x01},
+			opts: DecodeOptions{AllowNonCanonical: true},
+			want: map[String]Uint{"a": 1, "b": 2},
+		},
+		{
+			data: []byte{0xc6, 0xc2, 'a', 0x01, 0xc2, 'a', 0x02},
+			opts: DecodeOptions{AllowNonCanonical: true},
+			want: map[String]Uint{"a": 2},
+		},
+		{
+			data:    []byte{0xc2, 0xc1, 'a'},
+			wantErr: ErrUnexpectedNumberOfItems, wantPath: "[0]",
+		},
+		{
+			data:    []byte{0xc4, 0xc3, 'a', 0x01, 0x02},
+			wantErr: ErrUnexpectedNumberOfItems, wantPath: "[0][2]",
+		},
+		{
+			data:    []byte{0xc3, 0xc2, 'a', 0xc0},
+			wantErr: ErrUnsupportedType, wantPath: "[0][1]",
+		},
+		{data: []byte{0x80}, wantErr: ErrUnsupportedType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var m Map[String, Uint]
+			_, err := DecodeWithOptions(tt.data, &m, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			var decErr *DecodeError
+			if errors.As(err, &decErr) && decErr.PathString() != tt.wantPath {
+				t.Fatalf("expected path %q, got %q", tt.wantPath, decErr.PathString())
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if fmt.Sprint(m.Get()) != fmt.Sprint(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, m.Get())
+			}
+		})
+	}
+}
@@ -0,0 +1,560 @@
+package rlp
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	encoderType = reflect.TypeOf((*Encoder)(nil)).Elem()
+	decoderType = reflect.TypeOf((*Decoder)(nil)).Elem()
+	rlpPtrType  = reflect.TypeOf((*RLP)(nil))
+)
+
+// Marshal returns the RLP encoding of v.
+//
+// Values that implement the Encoder interface are encoded using their
+// EncodeRLP method. Other values are encoded using reflection:
+//
+//   - strings, byte slices and byte arrays are encoded as RLP strings,
+//   - unsigned integers and big.Int values are encoded as RLP integers,
+//   - booleans are encoded as in the Bool type, false as 0x80 and true as 0x01,
+//   - structs are encoded as RLP lists of their exported fields,
+//   - other slices and arrays are encoded as RLP lists of their elements,
+//   - pointers and interfaces are encoded as the values they point to.
+//
+// The encoding of struct fields can be customized using the "rlp" struct tag:
+//
+//   - "-" excludes the field from the encoding,
+//   - "optional" allows the field to be omitted; trailing optional fields
+//     with zero values are not encoded, and are set to zero values if they
+//     are missing during decoding; all fields that follow an optional field,
+//     except for a tail field, must be optional as well; nil pointers in
+//     optional fields that are followed by non-zero optional fields are
+//     encoded as empty strings, or as empty lists for struct, slice and
+//     array types,
+//   - "tail" may be used on the last field of a slice type; its elements are
+//     encoded directly into the struct list instead of as a nested list, and
+//     during decoding it receives all remaining list items.
+//
+// These tags have the same meaning as in the Ethereum reference
+// implementation, where they are used to add fields to block headers and
+// transactions in later forks.
+//
+// Trailing fields of the Optional type whose values are absent and use
+// AbsentOmitted are omitted in the same way, without the need for the tag.
+//
+// If v, or any value it refers to, is a nil pointer or a nil interface,
+// ErrNilValue is returned. The only exception are nil pointers in optional
+// fields, which are omitted or encoded as empty values as described above.
+// Nil interfaces in optional fields that are followed by non-zero optional
+// fields still cause ErrNilValue. Other types, such as signed integers,
+// floats and maps, are not supported and ErrUnsupportedType is returned.
+func Marshal(v any) ([]byte, error) {
+	if isNil(v) {
+		return nil, ErrNilValue
+	}
+	return appendValue(nil, reflect.ValueOf(v))
+}
+
+// Unmarshal decodes RLP item and stores the result in the value pointed to
+// by v. The rules are the same as for Marshal, values that implement the
+// Decoder interface are decoded using their DecodeRLP method.
+//
+// Nil pointers found in v are replaced with pointers to new values. Nil
+// interfaces are replaced with *RLP values that can be decoded further.
+// Slices are replaced with the decoded items, while arrays and structs must
+// have the same number of items as the decoded list, otherwise
+// ErrUnexpectedNumberOfItems is returned.
+//
+// The data must contain exactly one RLP item, otherwise
+// ErrUnexpectedTrailingData is returned.
+//
+// The decoded value may share memory with the input data, so the input data
+// must not be modified as long as the decoded value is in use.
+//
+// If v is nil, ErrNilValue is returned. If v is not a pointer,
+// ErrUnsupportedType is returned.
+func Unmarshal(data []byte, v any) error {
+	if isNil(v) {
+		return ErrNilValue
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return ErrUnsupportedType
+	}
+	n, err := decodeValue(data, rv.Elem())
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return ErrUnexpectedTrailingData
+	}
+	return nil
+}
+
+// field describes a struct field that is encoded as a list item.
+type field struct {
+	index    int
+	optional bool // The field may be omitted, see the "optional" tag.
+	tail     bool // The field holds the remaining items, see the "tail" tag.
+}
+
+// structInfo holds the fields of a struct type or an error if the struct
+// tags are invalid.
+type structInfo struct {
+	fields []field
+	err    error
+}
+
+// structFieldsCache caches the fields of struct types.
+var structFieldsCache sync.Map // map[reflect.Type]structInfo
+
+// structFields returns the fields of the given struct type that are
+// encoded as list items.
+func structFields(t reflect.Type) ([]field, error) {
+	if info, ok := structFieldsCache.Load(t); ok {
+		return info.(structInfo).fields, info.(structInfo).err
+	}
+	fields, err := parseStructFields(t)
+	structFieldsCache.Store(t, structInfo{fields: fields, err: err})
+	return fields, err
+}
+
+// parseStructFields parses the fields of the given struct type and their
+// "rlp" tags.
+func parseStructFields(t reflect.Type) ([]field, error) {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := field{index: i}
+		ignored := false
+		for _, tag := range strings.Split(sf.Tag.Get("rlp"), ",") {
+			switch strings.TrimSpace(tag) {
+			case "":
+			case "-":
+				ignored = true
+			case "optional":
+				f.optional = true
+			case "tail":
+				f.tail = true
+			default:
+				return nil, fmt.Errorf("%w: unknown tag %q on %v.%s", ErrInvalidStructTag, tag, t, sf.Name)
+			}
+		}
+		if ignored {
+			continue
+		}
+		if f.optional && f.tail {
+			return nil, fmt.Errorf("%w: %v.%s cannot be both optional and tail", ErrInvalidStructTag, t, sf.Name)
+		}
+		if f.tail && (sf.Type.Kind() != reflect.Slice || sf.Type.Elem().Kind() == reflect.Uint8) {
+			return nil, fmt.Errorf("%w: tail field %v.%s must be a non-byte slice", ErrInvalidStructTag, t, sf.Name)
+		}
+		if len(fields) > 0 {
+			prev := fields[len(fields)-1]
+			if prev.tail {
+				return nil, fmt.Errorf("%w: tail field %v.%s must be the last field", ErrInvalidStructTag, t, t.Field(prev.index).Name)
+			}
+			if prev.optional && !f.optional && !f.tail {
+				return nil, fmt.Errorf("%w: %v.%s must be optional because it follows an optional field", ErrInvalidStructTag, t, sf.Name)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// appendValue appends the RLP encoding of the given value to dst.
+func appendValue(dst []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return nil, ErrNilValue
+	}
+	t := v.Type()
+	if t.Implements(encoderType) {
+		return appendItem(dst, v.Interface())
+	}
+	if reflect.PointerTo(t).Implements(encoderType) {
+		return appendItem(dst, addrOf(v).Interface())
+	}
+	if t == bigIntType {
+		return appendBigInt(dst, addrOf(v).Interface().(*big.Int))
+	}
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, ErrNilValue
+		}
+		return appendValue(dst, v.Elem())
+	case reflect.String:
+		return appendString(dst, v.String())
+	case reflect.Bool:
+		return appendBool(dst, v.Bool())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return appendUint(dst, v.Uint())
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return appendBytes(dst, v.Bytes())
+		}
+		return appendListValue(dst, v)
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			for i := range b {
+				b[i] = byte(v.Index(i).Uint())
+			}
+			return appendBytes(dst, b)
+		}
+		return appendListValue(dst, v)
+	case reflect.Struct:
+		return appendStructValue(dst, v)
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
+
+// appendStructValue appends the RLP list item encoding of a struct to dst.
+//
+// Trailing optional fields with zero values are omitted. Items of the tail
+// field are encoded directly into the list.
+func appendStructValue(dst []byte, v reflect.Value) ([]byte, error) {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	n := len(fields)
+	for n > 0 {
+		f, fv := fields[n-1], v.Field(fields[n-1].index)
+		// An empty tail field adds no items, so it does not prevent
+		// trimming the optional fields before it.
+		if !(f.optional && fv.IsZero()) && !(f.tail && fv.Len() == 0) && !isOmitted(fv.Interface()) {
+			break
+		}
+		n--
+	}
+	start := len(dst)
+	dst = append(dst, 0)
+	for _, f := range fields[:n] {
+		fv := v.Field(f.index)
+		switch {
+		case f.tail:
+			dst, err = appendItemsValue(dst, fv)
+		case f.optional && fv.Kind() == reflect.Pointer && fv.IsNil():
+			// A nil optional field followed by a present optional field is
+			// encoded as an empty value, as in the reference implementation.
+			dst = append(dst, emptyValue(fv.Type().Elem()))
+		default:
+			dst, err = appendValue(dst, fv)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return finishList(dst, start)
+}
+
+// emptyValue returns the encoding of an empty value of the given type, which
+// is an empty list for types encoded as RLP lists, and an empty string for
+// other types. For types that implement the Encoder interface, the kind of
+// the encoded zero value is used.
+func emptyValue(t reflect.Type) byte {
+	if t == bigIntType {
+		return stringOffset
+	}
+	if t.Implements(encoderType) || reflect.PointerTo(t).Implements(encoderType) {
+		if b, err := appendValue(nil, reflect.New(t).Elem()); err == nil && len(b) > 0 {
+			if b[0] >= listOffset {
+				return listOffset
+			}
+			return stringOffset
+		}
+	}
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Struct:
+		return listOffset
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return listOffset
+		}
+	}
+	return stringOffset
+}
+
+// appendListValue appends the RLP list item encoding of a slice or an array
+// to dst.
+func appendListValue(dst []byte, v reflect.Value) ([]byte, error) {
+	start := len(dst)
+	dst = append(dst, 0)
+	dst, err := appendItemsValue(dst, v)
+	if err != nil {
+		return nil, err
+	}
+	return finishList(dst, start)
+}
+
+// appendItemsValue appends the RLP encodings of the elements of a slice or
+// an array to dst, without the list prefix.
+func appendItemsValue(dst []byte, v reflect.Value) ([]byte, error) {
+	for i := 0; i < v.Len(); i++ {
+		var err error
+		if dst, err = appendValue(dst, v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// decodeValue decodes RLP item into the given value. The value must be
+// settable.
+//
+// Errors are wrapped in a DecodeError with the type of the value, unless
+// they are already wrapped by a nested decoder.
+func decodeValue(src []byte, v reflect.Value) (int, error) {
+	n, err := decodeValueOf(src, v)
+	if err != nil {
+		return 0, decodeError(err, v.Type())
+	}
+	return n, nil
+}
+
+// decodeValueOf decodes RLP item into the given value.
+func decodeValueOf(src []byte, v reflect.Value) (int, error) {
+	t := v.Type()
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface && reflect.PointerTo(t).Implements(decoderType) {
+		return v.Addr().Interface().(Decoder).DecodeRLP(src)
+	}
+	if t == bigIntType {
+		return decodeBigInt(src, v.Addr().Interface().(*big.Int), nil)
+	}
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return decodeValue(src, v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			// The type of the item is not known, so it is decoded as a raw
+			// RLP item, the same way as List does.
+			if !rlpPtrType.AssignableTo(t) {
+				return 0, ErrUnsupportedType
+			}
+			r := new(RLP)
+			n, err := r.DecodeRLP(src)
+			if err != nil {
+				return 0, err
+			}
+			v.Set(reflect.ValueOf(r))
+			return n, nil
+		}
+		if v.Elem().Kind() != reflect.Pointer || v.Elem().IsNil() {
+			// Values stored in an interface are not settable.
+			return 0, ErrUnsupportedType
+		}
+		return decodeValue(src, v.Elem().Elem())
+	case reflect.String:
+		var s string
+		n, err := decodeString(src, &s, nil)
+		if err != nil {
+			return 0, err
+		}
+		v.SetString(s)
+		return n, nil
+	case reflect.Bool:
+		var b bool
+		n, err := decodeBool(src, &b, nil)
+		if err != nil {
+			return 0, err
+		}
+		v.SetBool(b)
+		return n, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		n, err := decodeUint(src, &u, nil)
+		if err != nil {
+			return 0, err
+		}
+		if v.OverflowUint(u) {
+			return 0, ErrTooLarge
+		}
+		v.SetUint(u)
+		return n, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			n, err := decodeBytes(src, &b, nil)
+			if err != nil {
+				return 0, err
+			}
+			v.SetBytes(b)
+			return n, nil
+		}
+		// The decoded items are appended to a new slice, so the destination
+		// is left unchanged if the decoding fails.
+		s := reflect.MakeSlice(t, 0, 0)
+		n, err := decodeListValue(src, func(i int, data []byte) (int, error) {
+			s = reflect.Append(s, reflect.Zero(t.Elem()))
+			return decodeValue(data, s.Index(i))
+		})
+		if err != nil {
+			return 0, err
+		}
+		if s.Len() == 0 {
+			// The data is an empty list.
+			s = reflect.Zero(t)
+		}
+		v.Set(s)
+		return n, nil
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			n, err := decodeBytes(src, &b, nil)
+			if err != nil {
+				return 0, err
+			}
+			if len(b) != v.Len() {
+				return 0, ErrInvalidLength
+			}
+			for i, c := range b {
+				v.Index(i).SetUint(uint64(c))
+			}
+			return n, nil
+		}
+		return decodeFixedListValue(src, v.Len(), v.Index)
+	case reflect.Struct:
+		return decodeStructValue(src, v)
+	default:
+		return 0, ErrUnsupportedType
+	}
+}
+
+// decodeStructValue decodes RLP list item into a struct.
+//
+// Optional fields that are missing in the data are set to their zero values.
+// The tail field receives all items that follow the preceding fields.
+func decodeStructValue(src []byte, v reflect.Value) (int, error) {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return 0, err
+	}
+	var (
+		next = 0 // Index of the next field to decode.
+		tail reflect.Value
+	)
+	n, err := decodeListValue(src, func(_ int, data []byte) (int, error) {
+		if next >= len(fields) {
+			// The data contains more items than expected.
+			return 0, ErrUnexpectedNumberOfItems
+		}
+		f := fields[next]
+		if !f.tail {
+			next++
+			return decodeValue(data, v.Field(f.index))
+		}
+		// The decoded items are appended to a new slice, so the destination
+		// is left unchanged if the decoding fails.
+		ft := v.Field(f.index).Type()
+		if !tail.IsValid() {
+			tail = reflect.MakeSlice(ft, 0, 0)
+		}
+		tail = reflect.Append(tail, reflect.Zero(ft.Elem()))
+		return decodeValue(data, tail.Index(tail.Len()-1))
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range fields[next:] {
+		fv := v.Field(f.index)
+		switch {
+		case f.tail:
+			if !tail.IsValid() {
+				tail = reflect.Zero(fv.Type())
+			}
+			fv.Set(tail)
+		case f.optional:
+			fv.Set(reflect.Zero(fv.Type()))
+		case decodeOmittedValue(fv):
+			// The field is an Optional value that can be omitted.
+		default:
+			// The data contains fewer items than expected.
+			return 0, ErrUnexpectedNumberOfItems
+		}
+	}
+	return n, nil
+}
+
+// decodeFixedListValue decodes RLP list item into n values returned by the
+// item function. The number of items in the data must be equal to n.
+func decodeFixedListValue(src []byte, n int, item func(int) reflect.Value) (int, error) {
+	count := 0
+	totalLen, err := decodeListValue(src, func(i int, data []byte) (int, error) {
+		if i >= n {
+			// The data contains more items than expected.
+			return 0, ErrUnexpectedNumberOfItems
+		}
+		count++
+		return decodeValue(data, item(i))
+	})
+	if err != nil {
+		return 0, err
+	}
+	if count < n {
+		// The data contains fewer items than expected.
+		return 0, ErrUnexpectedNumberOfItems
+	}
+	return totalLen, nil
+}
+
+// decodeListValue decodes RLP list item by calling the item function for
+// each item in the list. The item function receives the index of the item
+// and the remaining list payload, and returns the number of bytes read.
+func decodeListValue(src []byte, item func(int, []byte) (int, error)) (int, error) {
+	data, totalLen, err := decodeListPayload(src, nil)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; len(data) > 0; i++ {
+		offset := totalLen - len(data)
+		itemLen, err := item(i, data)
+		if err != nil {
+			return 0, itemError(err, i, offset, nil)
+		}
+		if itemLen <= 0 || itemLen > len(data) {
+			// The item must not be empty, otherwise the loop would never end,
+			// and it must not exceed the list payload.
+			return 0, itemError(ErrUnexpectedEndOfData, i, offset, nil)
+		}
+		data = data[itemLen:]
+	}
+	return totalLen, nil
+}
+
+// decodeOmittedValue calls the decodeOmitted method of the given value, if
+// it implements the omittableDecoder interface, and returns its result.
+func decodeOmittedValue(v reflect.Value) bool {
+	if !v.CanAddr() {
+		return false
+	}
+	o, ok := v.Addr().Interface().(omittableDecoder)
+	return ok && o.decodeOmitted()
+}
+
+// addrOf returns a pointer to the given value. If the value is not
+// addressable, a pointer to its copy is returned.
+func addrOf(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
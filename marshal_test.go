@@ -0,0 +1,358 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type marshalStruct struct {
+	Str   string
+	Num   uint64
+	Flag  bool
+	Bytes []byte
+	Big   *big.Int
+	List  []uint16
+	Inner *marshalInner
+
+	unexported uint64 //nolint:unused
+}
+
+type marshalInner struct {
+	Hash [4]byte
+	Item String
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		data    any
+		want    []byte
+		wantErr error
+	}{
+		{data: "", want: []byte{0x80}},
+		{data: "dog", want: []byte{0x83, 'd', 'o', 'g'}},
+		{data: uint8(0), want: []byte{0x80}},
+		{data: uint16(1024), want: []byte{0x82, 0x04, 0x00}},
+		{data: uint(127), want: []byte{0x7f}},
+		{data: false, want: []byte{0x80}},
+		{data: true, want: []byte{0x01}},
+		{data: []byte{}, want: []byte{0x80}},
+		{data: []byte{0x01, 0x02}, want: []byte{0x82, 0x01, 0x02}},
+		{data: [2]byte{0x01, 0x02}, want: []byte{0x82, 0x01, 0x02}},
+		{data: big.NewInt(1024), want: []byte{0x82, 0x04, 0x00}},
+		{data: *big.NewInt(0), want: []byte{0x80}},
+		{data: []string{}, want: []byte{0xc0}},
+		{data: []string{"dog", "cat"}, want: []byte{0xc8, 0x83, 'd', 'o', 'g', 0x83, 'c', 'a', 't'}},
+		{data: [2]uint64{1, 2}, want: []byte{0xc2, 0x01, 0x02}},
+		{data: [][]string{{}, {"a"}}, want: []byte{0xc3, 0xc0, 0xc1, 'a'}},
+		{data: []any{"a", uint8(1), String("b")}, want: []byte{0xc3, 'a', 0x01, 'b'}},
+		{data: ptr("dog"), want: []byte{0x83, 'd', 'o', 'g'}},
+		{data: String("dog"), want: []byte{0x83, 'd', 'o', 'g'}},
+		{data: List{String("a"), Uint(1)}, want: []byte{0xc2, 'a', 0x01}},
+		{data: struct{}{}, want: []byte{0xc0}},
+		{
+			data: marshalStruct{
+				Str:   "dog",
+				Num:   1,
+				Flag:  true,
+				Bytes: []byte{0x80},
+				Big:   big.NewInt(2),
+				List:  []uint16{3},
+				Inner: &marshalInner{Hash: [4]byte{1, 2, 3, 4}, Item: "a"},
+			},
+			want: []byte{0xd2, 0x83, 'd', 'o', 'g', 0x01, 0x01, 0x81, 0x80, 0x02, 0xc1, 0x03, 0xc6, 0x84, 0x01, 0x02, 0x03, 0x04, 'a'},
+		},
+		{data: nil, wantErr: ErrNilValue},
+		{data: (*string)(nil), wantErr: ErrNilValue},
+		{data: (*Uint)(nil), wantErr: ErrNilValue},
+		{data: []*string{nil}, wantErr: ErrNilValue},
+		{data: []any{nil}, wantErr: ErrNilValue},
+		{data: marshalStruct{}, wantErr: ErrNilValue},
+		{data: int64(1), wantErr: ErrUnsupportedType},
+		{data: 1.5, wantErr: ErrUnsupportedType},
+		{data: map[string]string{}, wantErr: ErrUnsupportedType},
+		{data: []int{1}, wantErr: ErrUnsupportedType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Marshal(tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Marshal() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("Marshal() got = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		dest    any
+		want    any
+		wantErr error
+	}{
+		{data: []byte{0x80}, dest: new(string), want: ptr("")},
+		{data: []byte{0x83, 'd', 'o', 'g'}, dest: new(string), want: ptr("dog")},
+		{data: []byte{0x80}, dest: new(uint8), want: ptr(uint8(0))},
+		{data: []byte{0x81, 0xff}, dest: new(uint8), want: ptr(uint8(255))},
+		{data: []byte{0x82, 0x01, 0x00}, dest: new(uint8), wantErr: ErrTooLarge},
+		{data: []byte{0x82, 0x04, 0x00}, dest: new(uint16), want: ptr(uint16(1024))},
+		{data: []byte{0x82, 0x00, 0x01}, dest: new(uint16), wantErr: ErrNonCanonicalEncoding},
+		{data: []byte{0x80}, dest: new(bool), want: ptr(false)},
+		{data: []byte{0x01}, dest: new(bool), want: ptr(true)},
+		{data: []byte{0x02}, dest: new(bool), wantErr: ErrInvalidBool},
+		{data: []byte{0x00}, dest: new(bool), wantErr: ErrInvalidBool},
+		{data: []byte{0x82, 0x01, 0x02}, dest: new([]byte), want: ptr([]byte{0x01, 0x02})},
+		{data: []byte{0x82, 0x01, 0x02}, dest: new([2]byte), want: ptr([2]byte{0x01, 0x02})},
+		{data: []byte{0x81, 0x80}, dest: new([2]byte), wantErr: ErrInvalidLength},
+		{data: []byte{0x82, 0x04, 0x00}, dest: new(*big.Int), want: ptr(big.NewInt(1024))},
+		{data: []byte{0xc0}, dest: new([]string), want: new([]string)},
+		{data: []byte{0xc8, 0x83, 'd', 'o', 'g', 0x83, 'c', 'a', 't'}, dest: new([]string), want: ptr([]string{"dog", "cat"})},
+		{data: []byte{0xc2, 0x01, 0x02}, dest: new([2]uint64), want: ptr([2]uint64{1, 2})},
+		{data: []byte{0xc1, 0x01}, dest: new([2]uint64), wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0xc3, 0x01, 0x02, 0x03}, dest: new([2]uint64), wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0xc3, 0xc0, 0xc1, 'a'}, dest: new([][]string), want: ptr([][]string{nil, {"a"}})},
+		{data: []byte{0xc2, 'a', 0x01}, dest: new([]any), want: ptr([]any{&RLP{'a'}, &RLP{0x01}})},
+		{data: []byte{0xc2, 'a', 0x01}, dest: ptr([]any{nil, nil}), want: ptr([]any{&RLP{'a'}, &RLP{0x01}})},
+		{data: []byte{0x83, 'd', 'o', 'g'}, dest: new(*string), want: ptr(ptr("dog"))},
+		{data: []byte{0x83, 'd', 'o', 'g'}, dest: new(String), want: ptr(String("dog"))},
+		{data: []byte{0xc2, 'a', 0x01}, dest: ptr(List{new(String), new(Uint)}), want: ptr(List{ptr(String("a")), ptr(Uint(1))})},
+		{data: []byte{0xc0}, dest: new(struct{}), want: new(struct{})},
+		{
+			data: []byte{0xd2, 0x83, 'd', 'o', 'g', 0x01, 0x01, 0x81, 0x80, 0x02, 0xc1, 0x03, 0xc6, 0x84, 0x01, 0x02, 0x03, 0x04, 'a'},
+			dest: new(marshalStruct),
+			want: &marshalStruct{
+				Str:   "dog",
+				Num:   1,
+				Flag:  true,
+				Bytes: []byte{0x80},
+				Big:   big.NewInt(2),
+				List:  []uint16{3},
+				Inner: &marshalInner{Hash: [4]byte{1, 2, 3, 4}, Item: "a"},
+			},
+		},
+		{data: []byte{0xc1, 0x80}, dest: new(marshalStruct), wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0x83, 'd', 'o', 'g', 0x80}, dest: new(string), wantErr: ErrUnexpectedTrailingData},
+		{data: []byte{0x83, 'd', 'o'}, dest: new(string), wantErr: ErrUnexpectedEndOfData},
+		{data: []byte{0xc0}, dest: new(string), wantErr: ErrUnsupportedType},
+		{data: []byte{0x80}, dest: new(int), wantErr: ErrUnsupportedType},
+		{data: []byte{0x80}, dest: "", wantErr: ErrUnsupportedType},
+		{data: []byte{0x80}, dest: nil, wantErr: ErrNilValue},
+		{data: []byte{0x80}, dest: (*string)(nil), wantErr: ErrNilValue},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			err := Unmarshal(tt.data, tt.dest)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.dest, tt.want) {
+				t.Fatalf("Unmarshal() got = %#v, want %#v", tt.dest, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	src := marshalStruct{
+		Str:   "foo",
+		Num:   1 << 40,
+		Bytes: bytes.Repeat([]byte{'a'}, 100),
+		Big:   new(big.Int).Lsh(big.NewInt(1), 200),
+		List:  []uint16{1, 2, 3},
+		Inner: &marshalInner{Item: "bar"},
+	}
+	enc, err := Marshal(src)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	var dst marshalStruct
+	if err := Unmarshal(enc, &dst); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if !reflect.DeepEqual(src, dst) {
+		t.Fatalf("expected %#v, got %#v", src, dst)
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, s := range [][]byte{
+		{0xc0},
+		{0xd2, 0x83, 'd', 'o', 'g', 0x01, 0x01, 0x81, 0x80, 0x02, 0xc1, 0x03, 0xc6, 0x84, 0x01, 0x02, 0x03, 0x04, 'a'},
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s []byte) {
+		var v marshalStruct
+		_ = Unmarshal(s, &v)
+	})
+}
+
+type taggedStruct struct {
+	A       uint64
+	Ignored string `rlp:"-"`
+	B       uint64 `rlp:"optional"`
+	C       []byte `rlp:"optional"`
+}
+
+type optionalPointerStruct struct {
+	A uint64
+	B *big.Int         `rlp:"optional"`
+	C *[]uint64        `rlp:"optional"`
+	D *Uint256         `rlp:"optional"`
+	E *TypedList[Uint] `rlp:"optional"`
+	F *taggedStruct    `rlp:"optional"`
+	G uint64           `rlp:"optional"`
+}
+
+type tailStruct struct {
+	A    string
+	Tail []uint64 `rlp:"tail"`
+}
+
+type optionalTailStruct struct {
+	A uint64
+	B uint64   `rlp:"optional"`
+	C []uint64 `rlp:"tail"`
+}
+
+func TestMarshalTags(t *testing.T) {
+	tests := []struct {
+		data    any
+		want    []byte
+		wantErr error
+	}{
+		{data: taggedStruct{A: 1, Ignored: "x"}, want: []byte{0xc1, 0x01}},
+		{data: taggedStruct{A: 1, B: 2}, want: []byte{0xc2, 0x01, 0x02}},
+		{data: taggedStruct{A: 1, C: []byte{3}}, want: []byte{0xc3, 0x01, 0x80, 0x03}},
+		{data: optionalPointerStruct{A: 1}, want: []byte{0xc1, 0x01}},
+		{data: optionalPointerStruct{A: 1, B: big.NewInt(2)}, want: []byte{0xc2, 0x01, 0x02}},
+		{data: optionalPointerStruct{A: 1, G: 5}, want: []byte{0xc7, 0x01, 0x80, 0xc0, 0x80, 0xc0, 0xc0, 0x05}},
+		{data: optionalPointerStruct{A: 1, C: &[]uint64{3}, G: 5}, want: []byte{0xc8, 0x01, 0x80, 0xc1, 0x03, 0x80, 0xc0, 0xc0, 0x05}},
+		{data: struct {
+			A any    `rlp:"optional"`
+			B uint64 `rlp:"optional"`
+		}{B: 1}, wantErr: ErrNilValue},
+		{data: tailStruct{A: "a"}, want: []byte{0xc1, 'a'}},
+		{data: tailStruct{A: "a", Tail: []uint64{1, 2}}, want: []byte{0xc3, 'a', 0x01, 0x02}},
+		{data: optionalTailStruct{A: 1}, want: []byte{0xc1, 0x01}},
+		{data: optionalTailStruct{A: 1, B: 2}, want: []byte{0xc2, 0x01, 0x02}},
+		{data: optionalTailStruct{A: 1, C: []uint64{3}}, want: []byte{0xc3, 0x01, 0x80, 0x03}},
+		{data: struct {
+			A uint64 `rlp:"foo"`
+		}{}, wantErr: ErrInvalidStructTag},
+		{data: struct {
+			A uint64 `rlp:"optional"`
+			B uint64
+		}{}, wantErr: ErrInvalidStructTag},
+		{data: struct {
+			A []uint64 `rlp:"tail"`
+			B uint64
+		}{}, wantErr: ErrInvalidStructTag},
+		{data: struct {
+			A uint64 `rlp:"tail"`
+		}{}, wantErr: ErrInvalidStructTag},
+		{data: struct {
+			A []uint64 `rlp:"optional,tail"`
+		}{}, wantErr: ErrInvalidStructTag},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Marshal(tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Marshal() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("Marshal() got = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTags(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		dest    any
+		want    any
+		wantErr error
+	}{
+		{data: []byte{0xc1, 0x01}, dest: &taggedStruct{Ignored: "x", B: 5}, want: &taggedStruct{A: 1, Ignored: "x"}},
+		{data: []byte{0xc2, 0x01, 0x02}, dest: new(taggedStruct), want: &taggedStruct{A: 1, B: 2}},
+		{data: []byte{0xc3, 0x01, 0x80, 0x03}, dest: new(taggedStruct), want: &taggedStruct{A: 1, C: []byte{3}}},
+		{data: []byte{0xc0}, dest: new(taggedStruct), wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0xc4, 0x01, 0x02, 0x03, 0x04}, dest: new(taggedStruct), wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0xc1, 'a'}, dest: &tailStruct{Tail: []uint64{1}}, want: &tailStruct{A: "a"}},
+		{data: []byte{0xc3, 'a', 0x01, 0x02}, dest: new(tailStruct), want: &tailStruct{A: "a", Tail: []uint64{1, 2}}},
+		{data: []byte{0xc0}, dest: new(tailStruct), wantErr: ErrUnexpectedNumberOfItems},
+		{data: []byte{0xc2, 'a', 0xc0}, dest: new(tailStruct), wantErr: ErrUnsupportedType},
+		{data: []byte{0xc1, 0x01}, dest: &optionalTailStruct{B: 5, C: []uint64{1}}, want: &optionalTailStruct{A: 1}},
+		{data: []byte{0xc2, 0x01, 0x02}, dest: new(optionalTailStruct), want: &optionalTailStruct{A: 1, B: 2}},
+		{data: []byte{0xc4, 0x01, 0x02, 0x03, 0x04}, dest: new(optionalTailStruct), want: &optionalTailStruct{A: 1, B: 2, C: []uint64{3, 4}}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			err := Unmarshal(tt.data, tt.dest)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.dest, tt.want) {
+				t.Fatalf("Unmarshal() got = %#v, want %#v", tt.dest, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDecodeError(t *testing.T) {
+	// ["a", [1, 65536]] decoded into a struct whose second field is []uint16.
+	data := []byte{0xc7, 'a', 0xc5, 0x01, 0x83, 0x01, 0x00, 0x00}
+	var dst struct {
+		A string
+		B []uint16
+	}
+	err := Unmarshal(data, &dst)
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if decErr.Offset != 4 {
+		t.Errorf("expected offset 4, got %v", decErr.Offset)
+	}
+	if decErr.PathString() != "[1][1]" {
+		t.Errorf("expected path [1][1], got %q", decErr.PathString())
+	}
+	if decErr.Type != reflect.TypeOf(uint16(0)) {
+		t.Errorf("expected type uint16, got %v", decErr.Type)
+	}
+}
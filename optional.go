@@ -0,0 +1,180 @@
+package rlp
+
+// Absent defines how an absent Optional value is represented in RLP. It is
+// implemented by AbsentEmptyString, AbsentEmptyList and AbsentOmitted.
+type Absent interface {
+	// absentPrefix returns the encoding of an absent value. It returns false
+	// if an absent value is omitted.
+	absentPrefix() (byte, bool)
+}
+
+// AbsentEmptyString represents an absent value as an empty string (0x80).
+type AbsentEmptyString struct{}
+
+// AbsentEmptyList represents an absent value as an empty list (0xC0).
+type AbsentEmptyList struct{}
+
+// AbsentOmitted represents an absent value by omitting it. This is only
+// possible at the end of a list or a struct, so that the remaining items are
+// not shifted. An absent value that cannot be omitted results in ErrNilValue.
+type AbsentOmitted struct{}
+
+func (AbsentEmptyString) absentPrefix() (byte, bool) { return stringOffset, true }
+func (AbsentEmptyList) absentPrefix() (byte, bool)   { return listOffset, true }
+func (AbsentOmitted) absentPrefix() (byte, bool)     { return 0, false }
+
+// Optional is a value of type T that may be absent.
+//
+// The *T type must implement the Decoder interface if the value is being
+// decoded, and T or *T must implement the Encoder interface if the value is
+// being encoded.
+//
+// The A type defines how an absent value is represented. Because it is a part
+// of the type, the zero value of Optional, including values created during
+// decoding, for example the items of VarTypedList, already expects the right
+// representation. A present value whose encoding is the same as the
+// representation of an absent value, for example Uint(0) and
+// AbsentEmptyString, is decoded as absent.
+type Optional[T any, A Absent] struct {
+	value T
+	valid bool
+}
+
+// Valid returns true if the value is present.
+func (o Optional[T, A]) Valid() bool {
+	return o.valid
+}
+
+// Get returns the value. If the value is absent, it returns the zero value.
+func (o Optional[T, A]) Get() T {
+	return o.value
+}
+
+// Ptr returns a pointer to the value.
+func (o *Optional[T, A]) Ptr() *T {
+	return &o.value
+}
+
+// Set sets the value and marks it as present.
+func (o *Optional[T, A]) Set(value T) {
+	o.value = value
+	o.valid = true
+}
+
+// Reset marks the value as absent.
+func (o *Optional[T, A]) Reset() {
+	var zero T
+	o.value = zero
+	o.valid = false
+}
+
+// EncodeRLP implements the Encoder interface.
+func (o Optional[T, A]) EncodeRLP() ([]byte, error) {
+	return o.AppendRLP(nil)
+}
+
+// AppendRLP implements the Appender interface.
+func (o Optional[T, A]) AppendRLP(dst []byte) ([]byte, error) {
+	if o.valid {
+		return appendItem(dst, encoderOf(o.value))
+	}
+	prefix, ok := o.absent()
+	if !ok {
+		return nil, ErrNilValue
+	}
+	return append(dst, prefix), nil
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the value cannot be encoded, it returns 0.
+func (o Optional[T, A]) EncodedSize() int {
+	if o.valid {
+		n, _ := encodedSize(encoderOf(o.value))
+		return n
+	}
+	if _, ok := o.absent(); !ok {
+		return 0
+	}
+	return 1
+}
+
+// DecodeRLP implements the Decoder interface.
+func (o *Optional[T, A]) DecodeRLP(data []byte) (int, error) {
+	return o.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (o *Optional[T, A]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	if len(data) == 0 {
+		return 0, ErrUnexpectedEndOfData
+	}
+	if prefix, ok := o.absent(); ok && data[0] == prefix {
+		o.Reset()
+		return 1, nil
+	}
+	dec, ok := any(&o.value).(Decoder)
+	if !ok {
+		return 0, ErrUnsupportedType
+	}
+	n, err := decodeItem(dec, data, s)
+	if err != nil {
+		return 0, err
+	}
+	o.valid = true
+	return n, nil
+}
+
+// absent returns the encoding of an absent value, see Absent.
+func (o Optional[T, A]) absent() (byte, bool) {
+	var a A
+	return a.absentPrefix()
+}
+
+// omitted implements the omittable interface.
+func (o Optional[T, A]) omitted() bool {
+	if o.valid {
+		return false
+	}
+	_, ok := o.absent()
+	return !ok
+}
+
+// decodeOmitted implements the omittableDecoder interface.
+func (o *Optional[T, A]) decodeOmitted() bool {
+	if _, ok := o.absent(); ok {
+		return false
+	}
+	o.Reset()
+	return true
+}
+
+// omittable is implemented by items that may be omitted from the end of a
+// list.
+type omittable interface {
+	// omitted returns true if the item is omitted from the encoding.
+	omitted() bool
+}
+
+// omittableDecoder is implemented by items that may be missing from the end
+// of a list.
+type omittableDecoder interface {
+	// decodeOmitted is called for an item that is missing from the end of
+	// the list. It returns false if the item cannot be omitted.
+	decodeOmitted() bool
+}
+
+// isOmitted returns true if the given item is omitted from the encoding.
+func isOmitted(item any) bool {
+	o, ok := item.(omittable)
+	return ok && !isNil(item) && o.omitted()
+}
+
+// trimOmitted returns the slice without the trailing items that are omitted
+// from the encoding.
+func trimOmitted[T any](src []T) []T {
+	for len(src) > 0 && isOmitted(src[len(src)-1]) {
+		src = src[:len(src)-1]
+	}
+	return src
+}
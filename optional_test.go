@@ -0,0 +1,201 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func optional[T any, A Absent](value *T) Optional[T, A] {
+	var o Optional[T, A]
+	if value != nil {
+		o.Set(*value)
+	}
+	return o
+}
+
+func TestOptionalEncode(t *testing.T) {
+	tests := []struct {
+		data    Encoder
+		want    []byte
+		wantErr error
+	}{
+		{data: optional[Uint, AbsentEmptyString](ptr(Uint(1))), want: []byte{0x01}},
+		{data: optional[Uint, AbsentEmptyString](nil), want: []byte{0x80}},
+		{data: optional[Uint, AbsentEmptyList](nil), want: []byte{0xc0}},
+		{data: optional[Uint, AbsentOmitted](nil), wantErr: ErrNilValue},
+		{data: optional[String, AbsentOmitted](ptr(String("dog"))), want: []byte{0x83, 'd', 'o', 'g'}},
+		{
+			data: TypedList[Optional[Uint, AbsentOmitted]]{
+				ptr(optional[Uint, AbsentOmitted](ptr(Uint(1)))),
+				ptr(optional[Uint, AbsentOmitted](nil)),
+				ptr(optional[Uint, AbsentOmitted](nil)),
+			},
+			want: []byte{0xc1, 0x01},
+		},
+		{
+			data: List{
+				optional[Uint, AbsentEmptyList](nil),
+				optional[Uint, AbsentOmitted](nil),
+			},
+			want: []byte{0xc1, 0xc0},
+		},
+		{
+			data: List{
+				optional[Uint, AbsentOmitted](nil),
+				Uint(1),
+			},
+			wantErr: ErrNilValue,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			size, err := EncodedSize(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+type optionalUint interface {
+	Decoder
+	Valid() bool
+	Get() Uint
+	Set(Uint)
+}
+
+func TestOptionalDecode(t *testing.T) {
+	tests := []struct {
+		data      []byte
+		item      optionalUint
+		want      Uint
+		wantValid bool
+		wantErr   error
+	}{
+		{data: []byte{0x01}, item: new(Optional[Uint, AbsentEmptyString]), want: 1, wantValid: true},
+		{data: []byte{0x80}, item: new(Optional[Uint, AbsentEmptyString]), wantValid: false},
+		{data: []byte{0x80}, item: new(Optional[Uint, AbsentEmptyList]), wantValid: true},
+		{data: []byte{0xc0}, item: new(Optional[Uint, AbsentEmptyList]), wantValid: false},
+		{data: []byte{0xc0}, item: new(Optional[Uint, AbsentEmptyString]), wantErr: ErrUnsupportedType},
+		{data: []byte{0x80}, item: new(Optional[Uint, AbsentOmitted]), wantValid: true},
+		{data: []byte{}, item: new(Optional[Uint, AbsentEmptyString]), wantErr: ErrUnexpectedEndOfData},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			item := tt.item
+			item.Set(42)
+			_, err := Decode(tt.data, item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if item.Valid() != tt.wantValid {
+				t.Fatalf("expected valid %v, got %v", tt.wantValid, item.Valid())
+			}
+			if item.Get() != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, item.Get())
+			}
+		})
+	}
+}
+
+func TestOptionalDecodeOmitted(t *testing.T) {
+	var a, b Optional[Uint, AbsentOmitted]
+	b.Set(42)
+	list := List{new(Uint), &a, &b}
+	if _, err := Decode([]byte{0xc2, 0x01, 0x02}, &list); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !a.Valid() || a.Get() != 2 {
+		t.Fatalf("expected 2, got %v (valid %v)", a.Get(), a.Valid())
+	}
+	if b.Valid() {
+		t.Fatal("expected absent value")
+	}
+
+	// Only items that use AbsentOmitted can be missing.
+	var c Optional[Uint, AbsentEmptyString]
+	list = List{new(Uint), &c}
+	if _, err := Decode([]byte{0xc1, 0x01}, &list); !errors.Is(err, ErrUnexpectedNumberOfItems) {
+		t.Fatalf("expected ErrUnexpectedNumberOfItems, got %v", err)
+	}
+}
+
+func TestOptionalMarshal(t *testing.T) {
+	type tx struct {
+		Nonce Uint
+		Gas   Optional[Uint, AbsentOmitted]
+		Tip   Optional[Uint, AbsentOmitted]
+	}
+	v := tx{Nonce: 1}
+	v.Gas.Set(2)
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := []byte{0xc2, 0x01, 0x02}; !bytes.Equal(data, want) {
+		t.Fatalf("expected %x, got %x", want, data)
+	}
+	var got tx
+	got.Tip.Set(3)
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.Nonce != 1 || got.Gas.Get() != 2 || !got.Gas.Valid() || got.Tip.Valid() {
+		t.Fatalf("unexpected result %+v", got)
+	}
+}
+
+func TestOptionalUnmarshalZero(t *testing.T) {
+	type hdr struct {
+		A Uint
+		B Optional[Uint, AbsentOmitted]
+	}
+	data, err := Marshal(hdr{A: 1})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := []byte{0xc1, 0x01}; !bytes.Equal(data, want) {
+		t.Fatalf("expected %x, got %x", want, data)
+	}
+	var got hdr
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.A != 1 || got.B.Valid() {
+		t.Fatalf("unexpected result %+v", got)
+	}
+}
+
+func TestOptionalVarTypedList(t *testing.T) {
+	var list VarTypedList[Optional[Uint, AbsentEmptyList]]
+	if _, err := Decode([]byte{0xc3, 0x01, 0xc0, 0x80}, &list); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(list))
+	}
+	if !list[0].Valid() || list[0].Get() != 1 {
+		t.Fatalf("expected 1, got %v (valid %v)", list[0].Get(), list[0].Valid())
+	}
+	if list[1].Valid() {
+		t.Fatal("expected absent value")
+	}
+	if !list[2].Valid() || list[2].Get() != 0 {
+		t.Fatalf("expected 0, got %v (valid %v)", list[2].Get(), list[2].Valid())
+	}
+}
@@ -0,0 +1,290 @@
+package rlp
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	ErrMaxDepthExceeded = errors.New("rlp: maximum nesting depth exceeded")
+	ErrMaxItemsExceeded = errors.New("rlp: maximum number of list items exceeded")
+	ErrMaxSizeExceeded  = errors.New("rlp: maximum size exceeded")
+)
+
+// DecodeOptions holds the options for decoding RLP data.
+//
+// The limits protect the decoder from malicious data, such as deeply nested
+// lists or lists with a huge number of small items. A zero value of a limit
+// means that there is no limit.
+type DecodeOptions struct {
+	// MaxDepth is the maximum nesting depth of lists. A top-level list has
+	// depth 1, a list nested in it has depth 2, and so on.
+	MaxDepth int
+
+	// MaxItems is the maximum number of items in a single list.
+	MaxItems int
+
+	// MaxSize is the maximum size of the decoded item in bytes, including
+	// its prefix.
+	MaxSize int
+
+	// AllowNonCanonical enables the lenient decoding mode, in which data that
+	// is not encoded in its canonical form is accepted instead of being
+	// rejected with ErrNonCanonicalEncoding. This includes integers with
+	// leading zero bytes, single bytes in the [0x00, 0x7F] range encoded with
+	// a prefix, and long form prefixes used for short items.
+	//
+	// Only the built-in types support the lenient mode. Custom types and
+	// items decoded later from the RLP type are decoded canonically.
+	AllowNonCanonical bool
+
+	// OnNonCanonical, if not nil, is called for every non-canonical encoding
+	// accepted in the lenient mode. The error wraps ErrNonCanonicalEncoding
+	// and describes the location of the item in the input data by its offset
+	// and path. Non-canonical prefixes are reported before decoding, and
+	// non-canonical contents, like integers with leading zeros, are reported
+	// during decoding along with the type of the item.
+	OnNonCanonical func(err *DecodeError)
+}
+
+// DecodeWithOptions works like Decode, but it verifies that the data does not
+// exceed the limits set in the options before decoding it.
+//
+// The structure of the data is verified in advance, without recursion, so
+// exceeding a limit results in an error instead of exhausting the stack or
+// memory.
+func DecodeWithOptions(src []byte, dst Decoder, opts DecodeOptions) (int, error) {
+	if isNil(dst) {
+		return 0, ErrNilValue
+	}
+	if _, err := walk(src, &opts); err != nil {
+		return 0, err
+	}
+	n, err := decodeItem(dst, src, &decodeState{opts: &opts, input: src})
+	if err != nil {
+		return 0, err
+	}
+	if n != len(src) {
+		return 0, ErrUnexpectedTrailingData
+	}
+	return n, nil
+}
+
+// DecodeLazyWithOptions works like DecodeLazy, but it verifies that the data
+// does not exceed the limits set in the options.
+//
+// The options are not stored in the returned RLP value, so its methods, such
+// as List, Iter, Uint or BigInt, decode without limits. Because the limits
+// are verified for the whole item, including all items nested in it, these
+// methods cannot exceed them when used on the returned value or on the items
+// obtained from it. Other RLP values, for example those decoded with
+// DecodeLazy, should be checked with Validate before they are accessed. The
+// lenient mode does not apply to the methods either, so they reject
+// non-canonical data even if AllowNonCanonical is set.
+func DecodeLazyWithOptions(src []byte, opts DecodeOptions) (r RLP, n int, err error) {
+	if _, err = walk(src, &opts); err != nil {
+		return nil, 0, err
+	}
+	n, err = r.decodeRLPState(src, &decodeState{opts: &opts, input: src})
+	return
+}
+
+// Validate checks whether data contains exactly one well-formed RLP item. The
+// prefixes of the item and all items nested in it are verified to be encoded
+// canonically, the payloads of lists must consist exactly of their items, and
+// the limits set in the options must not be exceeded.
+//
+// Since the types of the items are not known, the contents of strings, for
+// example integers with leading zeros, are not verified.
+//
+// The data is verified without recursion and without allocating memory for
+// each item. The first error found is returned as a DecodeError, which holds
+// the offset of the invalid item.
+func Validate(data []byte, opts DecodeOptions) error {
+	n, err := walk(data, &opts)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return &DecodeError{Offset: n, Err: ErrUnexpectedTrailingData}
+	}
+	return nil
+}
+
+// decodeState holds the state of decoding with options. A nil *decodeState
+// is valid and means decoding with the default options.
+type decodeState struct {
+	opts  *DecodeOptions
+	input []byte // Data passed to the decoding function.
+	path  []int  // Indices of the list items being decoded.
+}
+
+// stateDecoder is implemented by the built-in types that support decoding
+// with options.
+type stateDecoder interface {
+	// decodeRLPState works like DecodeRLP, but it uses the given state.
+	decodeRLPState(data []byte, s *decodeState) (int, error)
+}
+
+// decodeItem decodes data into dst using the given state, if dst supports
+// it. Otherwise, it uses the DecodeRLP method.
+func decodeItem(dst Decoder, data []byte, s *decodeState) (int, error) {
+	if s != nil {
+		if d, ok := dst.(stateDecoder); ok {
+			return d.decodeRLPState(data, s)
+		}
+	}
+	return dst.DecodeRLP(data)
+}
+
+// enter records that the item at the given index of the current list is
+// being decoded. It must be followed by a call to leave.
+func (s *decodeState) enter(index int) {
+	if s != nil {
+		s.path = append(s.path, index)
+	}
+}
+
+// leave records that decoding of the item recorded by enter has finished.
+func (s *decodeState) leave() {
+	if s != nil {
+		s.path = s.path[:len(s.path)-1]
+	}
+}
+
+// lenient returns true if non-canonical encodings are accepted.
+func (s *decodeState) lenient() bool {
+	return s != nil && s.opts.AllowNonCanonical
+}
+
+// decodePrefix works like the decodePrefix function, but it accepts
+// non-canonical prefixes in the lenient mode. Such prefixes are not reported,
+// because walk reports them before decoding.
+func (s *decodeState) decodePrefix(prefix []byte) (offset byte, dataLen uint64, prefixLen uint8, err error) {
+	return parsePrefix(prefix, !s.lenient())
+}
+
+// canonicalInt verifies that the big endian integer b, which is the content
+// of the given item, is encoded canonically. In the lenient mode, the leading
+// zero bytes are reported and removed instead.
+func (s *decodeState) canonicalInt(item, b []byte, typ reflect.Type) ([]byte, error) {
+	if err := verifyCanonicalInt(b); err != nil {
+		if err := s.nonCanonical(item, typ); err != nil {
+			return nil, err
+		}
+	}
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b, nil
+}
+
+// canonicalUint works like canonicalInt, but it also verifies that the
+// integer fits into the given number of bytes. In the canonical mode, the
+// size is verified first, so an integer that is too large is reported as
+// such even if it also has leading zero bytes.
+func (s *decodeState) canonicalUint(item, b []byte, size int, typ reflect.Type) ([]byte, error) {
+	if len(b) > size && !s.lenient() {
+		return nil, ErrTooLarge
+	}
+	b, err := s.canonicalInt(item, b, typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > size {
+		return nil, ErrTooLarge
+	}
+	return b, nil
+}
+
+// nonCanonical handles the given item whose content is not encoded
+// canonically. In the canonical mode, it returns ErrNonCanonicalEncoding. In
+// the lenient mode, it reports the item and returns nil.
+func (s *decodeState) nonCanonical(item []byte, typ reflect.Type) error {
+	if !s.lenient() {
+		return ErrNonCanonicalEncoding
+	}
+	if s.opts.OnNonCanonical != nil {
+		// The item is a subslice of the input data, so its offset can be
+		// computed from the capacities of both slices.
+		s.opts.OnNonCanonical(&DecodeError{
+			Offset: cap(s.input) - cap(item),
+			Path:   append([]int(nil), s.path...),
+			Type:   typ,
+			Err:    ErrNonCanonicalEncoding,
+		})
+	}
+	return nil
+}
+
+// walkLevel describes a list entered by walk.
+type walkLevel struct {
+	end   int // Offset of the end of the list payload.
+	items int // Number of items found so far.
+}
+
+// walk traverses the first RLP item in src, and all items nested in it,
+// without recursion. It verifies that the prefixes of all items are valid,
+// that the items fit into their lists, and that the limits set in opts are
+// not exceeded. It returns the length of the item.
+func walk(src []byte, opts *DecodeOptions) (int, error) {
+	var (
+		buf   [16]walkLevel
+		stack = buf[:0]
+		pos   = 0
+	)
+	for {
+		limit := len(src)
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			top.items++
+			if opts.MaxItems > 0 && top.items > opts.MaxItems {
+				return 0, walkError(ErrMaxItemsExceeded, pos, stack)
+			}
+			limit = top.end
+		}
+		offset, dataLen, prefixLen, err := decodePrefix(src[pos:limit])
+		if errors.Is(err, ErrNonCanonicalEncoding) && opts.AllowNonCanonical {
+			if opts.OnNonCanonical != nil {
+				opts.OnNonCanonical(walkError(err, pos, stack))
+			}
+			offset, dataLen, prefixLen, err = parsePrefix(src[pos:limit], false)
+		}
+		if err != nil {
+			return 0, walkError(err, pos, stack)
+		}
+		totalLen := dataLen + uint64(prefixLen)
+		if totalLen > uint64(limit-pos) {
+			return 0, walkError(ErrUnexpectedEndOfData, pos, stack)
+		}
+		if len(stack) == 0 && opts.MaxSize > 0 && totalLen > uint64(opts.MaxSize) {
+			return 0, walkError(ErrMaxSizeExceeded, pos, stack)
+		}
+		if offset == listOffset {
+			if opts.MaxDepth > 0 && len(stack) >= opts.MaxDepth {
+				return 0, walkError(ErrMaxDepthExceeded, pos, stack)
+			}
+			stack = append(stack, walkLevel{end: pos + int(totalLen)})
+			pos += int(prefixLen)
+		} else {
+			pos += int(totalLen)
+		}
+		// Leave all lists that end at the current position.
+		for len(stack) > 0 && stack[len(stack)-1].end == pos {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			return pos, nil
+		}
+	}
+}
+
+// walkError returns a DecodeError for an item at the given offset found by
+// walk in the given lists.
+func walkError(err error, offset int, stack []walkLevel) *DecodeError {
+	var path []int
+	for _, l := range stack {
+		path = append(path, l.items-1)
+	}
+	return &DecodeError{Offset: offset, Path: path, Err: err}
+}
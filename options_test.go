@@ -0,0 +1,383 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestDecodeWithOptions(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		dest     Decoder
+		opts     DecodeOptions
+		wantErr  error
+		wantPath string
+	}{
+		{
+			data: []byte{0xc3, 0xc2, 0xc1, 0xc0},
+			dest: new(RLP),
+			opts: DecodeOptions{MaxDepth: 4},
+		},
+		{
+			data:    []byte{0xc3, 0xc2, 0xc1, 0xc0},
+			dest:    new(RLP),
+			opts:    DecodeOptions{MaxDepth: 3},
+			wantErr: ErrMaxDepthExceeded, wantPath: "[0][0][0]",
+		},
+		{
+			data: []byte{0xc3, 0x80, 0x80, 0x80},
+			dest: new(VarList),
+			opts: DecodeOptions{MaxItems: 3},
+		},
+		{
+			data:    []byte{0xc3, 0x80, 0x80, 0x80},
+			dest:    new(VarList),
+			opts:    DecodeOptions{MaxItems: 2},
+			wantErr: ErrMaxItemsExceeded, wantPath: "[2]",
+		},
+		{
+			data:    []byte{0xc4, 0xc3, 0x80, 0x80, 0x80},
+			dest:    new(VarList),
+			opts:    DecodeOptions{MaxItems: 2},
+			wantErr: ErrMaxItemsExceeded, wantPath: "[0][2]",
+		},
+		{
+			data: []byte{0x83, 'd', 'o', 'g'},
+			dest: new(String),
+			opts: DecodeOptions{MaxSize: 4},
+		},
+		{
+			data:    []byte{0x83, 'd', 'o', 'g'},
+			dest:    new(String),
+			opts:    DecodeOptions{MaxSize: 3},
+			wantErr: ErrMaxSizeExceeded,
+		},
+		{
+			data:    []byte{0xc2, 0x80, 0x81},
+			dest:    new(RLP),
+			wantErr: ErrUnexpectedEndOfData, wantPath: "[1]",
+		},
+		{
+			data:    []byte{0xc2, 0xc2, 0x80, 0x80},
+			dest:    new(RLP),
+			wantErr: ErrUnexpectedEndOfData, wantPath: "[0]",
+		},
+		{
+			data:    []byte{0xc2, 0x81, 0x01},
+			dest:    new(RLP),
+			wantErr: ErrNonCanonicalEncoding, wantPath: "[0]",
+		},
+		{
+			data:    []byte{0x80, 0x80},
+			dest:    new(String),
+			wantErr: ErrUnexpectedTrailingData,
+		},
+		{
+			data:    []byte{0x80},
+			dest:    (*String)(nil),
+			wantErr: ErrNilValue,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			_, err := DecodeWithOptions(tt.data, tt.dest, tt.opts)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("DecodeWithOptions() unexpected error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			var decErr *DecodeError
+			if errors.As(err, &decErr) && decErr.PathString() != tt.wantPath {
+				t.Fatalf("DecodeWithOptions() path = %q, want %q", decErr.PathString(), tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestDecodeWithOptionsDeepNesting(t *testing.T) {
+	const depth = 10_000
+	data := nestedList(depth)
+	_, err := DecodeWithOptions(data, new(RLP), DecodeOptions{MaxDepth: 128})
+	if !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Fatalf("expected ErrMaxDepthExceeded, got %v", err)
+	}
+	// Without the limit, the structure is verified without recursion.
+	if _, err := DecodeWithOptions(data, new(RLP), DecodeOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeLazyWithOptions(t *testing.T) {
+	data := []byte{0xc2, 0xc1, 0xc0, 0x80}
+	r, n, err := DecodeLazyWithOptions(data, DecodeOptions{MaxDepth: 3})
+	if err != nil {
+		t.Fatalf("DecodeLazyWithOptions() failed: %v", err)
+	}
+	if n != 3 || !bytes.Equal(r, data[:3]) {
+		t.Fatalf("DecodeLazyWithOptions() = %x, %v", r, n)
+	}
+	if _, _, err := DecodeLazyWithOptions(data, DecodeOptions{MaxDepth: 2}); !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Fatalf("expected ErrMaxDepthExceeded, got %v", err)
+	}
+	var list VarTypedList[RLP]
+	if err := r.DecodeWithOptions(&list, DecodeOptions{MaxItems: 1}); err != nil {
+		t.Fatalf("DecodeWithOptions() failed: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 item, got %v", len(list))
+	}
+	if err := r.DecodeWithOptions(&list, DecodeOptions{MaxDepth: 1}); !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Fatalf("expected ErrMaxDepthExceeded, got %v", err)
+	}
+
+	// The limits are verified for nested items, which can be accessed later
+	// without options.
+	nested := []byte{0xc3, 0xc2, 0x02, 0x03}
+	if _, _, err := DecodeLazyWithOptions(nested, DecodeOptions{MaxItems: 1}); !errors.Is(err, ErrMaxItemsExceeded) {
+		t.Fatalf("expected ErrMaxItemsExceeded, got %v", err)
+	}
+}
+
+// nestedList returns an encoding of the given number of nested empty lists.
+func nestedList(depth int) []byte {
+	enc := []byte{0xc0}
+	for i := 1; i < depth; i++ {
+		prefix, _ := appendPrefix(nil, uint64(len(enc)), listOffset)
+		enc = append(prefix, enc...)
+	}
+	return enc
+}
+
+func TestDecodeWithOptionsNonCanonical(t *testing.T) {
+	tests := []struct {
+		data        []byte
+		dest        Decoder
+		want        Encoder
+		wantOffsets []int
+		strictErr   error // Error in the canonical mode, ErrNonCanonicalEncoding if nil.
+	}{
+		// Integer with leading zeros.
+		{data: []byte{0x82, 0x00, 0x01}, dest: new(Uint), want: Uint(1), wantOffsets: []int{0}},
+		{data: []byte{0x82, 0x00, 0x00}, dest: new(Uint), want: Uint(0), wantOffsets: []int{0}},
+		{data: []byte{0x82, 0x00, 0xff}, dest: new(BigInt), want: (*BigInt)(big.NewInt(0xff)), wantOffsets: []int{0}},
+		// Integer with leading zeros longer than 8 bytes, which is too large
+		// in the canonical mode.
+		{data: []byte{0x89, 0x00, 0, 0, 0, 0, 0, 0, 0, 0x01}, dest: new(Uint), want: Uint(1), wantOffsets: []int{0}, strictErr: ErrTooLarge},
+		// Two's complement integer with a redundant sign byte.
+		{data: []byte{0x82, 0xff, 0x80}, dest: new(TwosComplementInt), want: TwosComplementInt(-128), wantOffsets: []int{0}},
+		// Single byte encoded with a prefix.
+		{data: []byte{0x81, 0x01}, dest: new(String), want: String("\x01"), wantOffsets: []int{0}},
+		// Bool encoded with a prefix.
+		{data: []byte{0x81, 0x01}, dest: new(Bool), want: Bool(true), wantOffsets: []int{0}},
+		{
+			data:        []byte{0xc3, 0x80, 0x81, 0x01},
+			dest:        new(VarTypedList[Bool]),
+			want:        VarTypedList[Bool]{ptr(Bool(false)), ptr(Bool(true))},
+			wantOffsets: []int{2},
+		},
+		// Long form prefix for a short string.
+		{data: []byte{0xb8, 0x03, 'd', 'o', 'g'}, dest: new(String), want: String("dog"), wantOffsets: []int{0}},
+		// Non-canonical items in a list.
+		{
+			data:        []byte{0xf8, 0x05, 0x81, 0x01, 0x82, 0x00, 0x02},
+			dest:        new(VarTypedList[Uint]),
+			want:        VarTypedList[Uint]{ptr(Uint(1)), ptr(Uint(2))},
+			wantOffsets: []int{0, 2, 4},
+		},
+		// Canonical data is not reported.
+		{data: []byte{0xc2, 0x01, 0x02}, dest: new(VarTypedList[Uint]), want: VarTypedList[Uint]{ptr(Uint(1)), ptr(Uint(2))}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			// Canonical mode must reject non-canonical data.
+			if len(tt.wantOffsets) > 0 {
+				strictErr := tt.strictErr
+				if strictErr == nil {
+					strictErr = ErrNonCanonicalEncoding
+				}
+				if _, err := DecodeWithOptions(tt.data, tt.dest, DecodeOptions{}); !errors.Is(err, strictErr) {
+					t.Fatalf("expected %v, got %v", strictErr, err)
+				}
+			}
+			var offsets []int
+			opts := DecodeOptions{
+				AllowNonCanonical: true,
+				OnNonCanonical: func(err *DecodeError) {
+					if !errors.Is(err, ErrNonCanonicalEncoding) {
+						t.Errorf("unexpected warning: %v", err)
+					}
+					offsets = append(offsets, err.Offset)
+				},
+			}
+			if _, err := DecodeWithOptions(tt.data, tt.dest, opts); err != nil {
+				t.Fatalf("DecodeWithOptions() unexpected error = %v", err)
+			}
+			got, err := Encode(tt.dest.(Encoder))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := Encode(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("DecodeWithOptions() = %x, want %x", got, want)
+			}
+			if fmt.Sprint(offsets) != fmt.Sprint(tt.wantOffsets) {
+				t.Fatalf("warnings at offsets %v, want %v", offsets, tt.wantOffsets)
+			}
+		})
+	}
+}
+
+func TestDecodeWithOptionsNonCanonicalPath(t *testing.T) {
+	tests := []struct {
+		data []byte
+		dest Decoder
+		want []string
+	}{
+		// A non-canonical prefix is reported by walk and an integer with
+		// leading zeros is reported during decoding, both with their paths.
+		{
+			data: []byte{0xc5, 0x82, 0x00, 0x05, 0x81, 0x05},
+			dest: &List{new(Uint), new(Uint)},
+			want: []string{"4 [1]", "1 [0]"},
+		},
+		{
+			data: []byte{0xc5, 0x01, 0xc3, 0x82, 0x00, 0x05},
+			dest: new(Tuple2[Uint, VarTypedList[Uint]]),
+			want: []string{"3 [1 0]"},
+		},
+		// Map entries are reported with the index of the pair and of the key
+		// or value in the pair.
+		{
+			data: []byte{0xc5, 0xc4, 'a', 0x82, 0x00, 0x05},
+			dest: new(Map[String, Uint]),
+			want: []string{"3 [0 1]"},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var got []string
+			opts := DecodeOptions{
+				AllowNonCanonical: true,
+				OnNonCanonical: func(err *DecodeError) {
+					got = append(got, fmt.Sprint(err.Offset, " ", err.Path))
+				},
+			}
+			if _, err := DecodeWithOptions(tt.data, tt.dest, opts); err != nil {
+				t.Fatalf("DecodeWithOptions() unexpected error = %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("warnings %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeWithOptionsBool(t *testing.T) {
+	tests := []struct {
+		data        []byte
+		want        Bool
+		wantOffsets []int
+		wantErr     error
+	}{
+		{data: []byte{0x80}, want: false},
+		{data: []byte{0x01}, want: true},
+		{data: []byte{0x00}, want: false, wantOffsets: []int{0}},
+		{data: []byte{0x82, 0x00, 0x01}, want: true, wantOffsets: []int{0}},
+		{data: []byte{0x02}, wantErr: ErrInvalidBool},
+		{data: []byte{0x82, 0x01, 0x00}, wantErr: ErrInvalidBool},
+		{data: []byte{0xc0}, wantErr: ErrUnsupportedType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var (
+				got     Bool
+				offsets []int
+			)
+			opts := DecodeOptions{
+				AllowNonCanonical: true,
+				OnNonCanonical: func(err *DecodeError) {
+					offsets = append(offsets, err.Offset)
+				},
+			}
+			_, err := DecodeWithOptions(tt.data, &got, opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			if fmt.Sprint(offsets) != fmt.Sprint(tt.wantOffsets) {
+				t.Fatalf("warnings at offsets %v, want %v", offsets, tt.wantOffsets)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		data       []byte
+		opts       DecodeOptions
+		wantErr    error
+		wantOffset int
+		wantPath   string
+	}{
+		{data: []byte{0x80}},
+		{data: []byte{0xc7, 0x83, 'd', 'o', 'g', 0xc1, 0x01, 0x80}},
+		{data: append([]byte{0xb8, 0x38}, make([]byte, 56)...)},
+		{data: []byte{}, wantErr: ErrUnexpectedEndOfData},
+		{data: []byte{0x80, 0x80}, wantErr: ErrUnexpectedTrailingData, wantOffset: 1},
+		{data: []byte{0xc3, 0x01, 0x02}, wantErr: ErrUnexpectedEndOfData, wantOffset: 0},
+		{data: []byte{0xc3, 0x01, 0x82, 0x02}, wantErr: ErrUnexpectedEndOfData, wantOffset: 2, wantPath: "[1]"},
+		{data: []byte{0xc3, 0xc2, 0x01, 0x02, 0x03}, wantErr: ErrUnexpectedTrailingData, wantOffset: 4},
+		{data: []byte{0xc4, 0x01, 0xc2, 0x81, 0x01}, wantErr: ErrNonCanonicalEncoding, wantOffset: 3, wantPath: "[1][0]"},
+		{data: []byte{0xb8, 0x03, 'd', 'o', 'g'}, wantErr: ErrNonCanonicalEncoding},
+		{data: []byte{0xb8, 0x03, 'd', 'o', 'g'}, opts: DecodeOptions{AllowNonCanonical: true}},
+		{data: []byte{0xc2, 0xc1, 0xc0}, opts: DecodeOptions{MaxDepth: 2}, wantErr: ErrMaxDepthExceeded, wantOffset: 2, wantPath: "[0][0]"},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			err := Validate(tt.data, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var decErr *DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("Validate() error = %v, want DecodeError", err)
+			}
+			if decErr.Offset != tt.wantOffset || decErr.PathString() != tt.wantPath {
+				t.Fatalf("Validate() offset = %d, path = %q, want %d, %q", decErr.Offset, decErr.PathString(), tt.wantOffset, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestValidateAllocs(t *testing.T) {
+	data, err := Encode(VarTypedList[VarTypedList[Uint]]{
+		{ptr(Uint(1)), ptr(Uint(2))},
+		{ptr(Uint(3)), ptr(Uint(1000))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := Validate(data, DecodeOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
@@ -1,11 +1,12 @@
 package rlp
 
 import (
-	"bytes"
 	"errors"
 	"math"
 	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -16,8 +17,107 @@ var (
 	ErrNonCanonicalEncoding    = errors.New("rlp: non-canonical encoding")
 	ErrNilValue                = errors.New("rlp: nil value")
 	ErrTooLarge                = errors.New("rlp: value too large")
+	ErrInvalidLength           = errors.New("rlp: invalid length")
+	ErrInvalidStructTag        = errors.New("rlp: invalid struct tag")
+	ErrInvalidBool             = errors.New("rlp: invalid boolean value")
+	ErrNegativeValue           = errors.New("rlp: negative value")
+	ErrDuplicateKey            = errors.New("rlp: duplicate map key")
 )
 
+// Types of values decoded by the built-in decoders, reported in DecodeError.
+var (
+	rlpType      = reflect.TypeOf(RLP(nil))
+	bytesType    = reflect.TypeOf([]byte(nil))
+	bytes20Type  = reflect.TypeOf([20]byte{})
+	bytes32Type  = reflect.TypeOf([32]byte{})
+	bytes256Type = reflect.TypeOf([256]byte{})
+	stringType   = reflect.TypeOf("")
+	uint8Type    = reflect.TypeOf(uint8(0))
+	uint16Type   = reflect.TypeOf(uint16(0))
+	uint32Type   = reflect.TypeOf(uint32(0))
+	uint64Type   = reflect.TypeOf(uint64(0))
+	int64Type    = reflect.TypeOf(int64(0))
+	boolType     = reflect.TypeOf(false)
+	bigIntType   = reflect.TypeOf(big.Int{})
+	uint256Type  = reflect.TypeOf(Uint256{})
+)
+
+// DecodeError describes an error that occurred while decoding RLP data.
+//
+// DecodeError wraps one of the errors defined in this package, or an error
+// returned by the DecodeRLP method of a custom type, so it can be matched
+// using errors.Is.
+type DecodeError struct {
+	Offset int          // Offset of the invalid item in the input data.
+	Path   []int        // Indices of the invalid item in the nested lists.
+	Type   reflect.Type // Go type into which the item was decoded, if known.
+	Err    error        // Underlying error.
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	b.WriteString(" (offset ")
+	b.WriteString(strconv.Itoa(e.Offset))
+	if len(e.Path) > 0 {
+		b.WriteString(", path ")
+		b.WriteString(e.PathString())
+	}
+	if e.Type != nil {
+		b.WriteString(", type ")
+		b.WriteString(e.Type.String())
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// PathString returns the path of the invalid item in the form of list
+// indices, such as "[3][0][8]".
+func (e *DecodeError) PathString() string {
+	var b strings.Builder
+	for _, i := range e.Path {
+		b.WriteString("[")
+		b.WriteString(strconv.Itoa(i))
+		b.WriteString("]")
+	}
+	return b.String()
+}
+
+// decodeError wraps the given error in a DecodeError for an item of the given
+// type. If the error is already a DecodeError, it is returned as is.
+func decodeError(err error, typ reflect.Type) error {
+	if _, ok := err.(*DecodeError); ok { //nolint:errorlint
+		return err
+	}
+	return &DecodeError{Type: typ, Err: err}
+}
+
+// itemError wraps the given error in a DecodeError for a list item at the
+// given index and offset relative to the beginning of the list. If the error
+// is already a DecodeError, the item index is prepended to its path and the
+// offset is added to its offset.
+func itemError(err error, index, offset int, typ reflect.Type) error {
+	e, ok := err.(*DecodeError) //nolint:errorlint
+	if !ok {
+		e = &DecodeError{Type: typ, Err: err}
+	}
+	path := make([]int, 0, len(e.Path)+1)
+	path = append(path, index)
+	path = append(path, e.Path...)
+	return &DecodeError{
+		Offset: e.Offset + offset,
+		Path:   path,
+		Type:   e.Type,
+		Err:    e.Err,
+	}
+}
+
 // Encoder is the interface implemented by types that can marshal themselves
 // into RLP.
 type Encoder interface {
@@ -37,6 +137,26 @@ type Decoder interface {
 	DecodeRLP([]byte) (int, error)
 }
 
+// Appender is the interface implemented by types that can append their RLP
+// encoding to a byte slice.
+//
+// Appending makes it possible to encode many values into a single reusable
+// buffer, without allocating memory for the encoding of each value.
+type Appender interface {
+	// AppendRLP appends the RLP encoding of the value to dst and returns the
+	// extended slice.
+	AppendRLP(dst []byte) ([]byte, error)
+}
+
+// Sizer is the interface implemented by types that can compute the length of
+// their RLP encoding without encoding themselves.
+//
+// The result must be equal to the length of the data returned by EncodeRLP.
+type Sizer interface {
+	// EncodedSize returns the length of the RLP encoding of the value.
+	EncodedSize() int
+}
+
 // Encode encodes the given value into an RLP item.
 //
 // If src is nil, ErrNilValue is returned.
@@ -47,6 +167,35 @@ func Encode(src Encoder) ([]byte, error) {
 	return src.EncodeRLP()
 }
 
+// Append appends the RLP encoding of the given value to dst and returns the
+// extended slice.
+//
+// If the value implements the Appender interface, its AppendRLP method is
+// used, otherwise the result of the EncodeRLP method is appended.
+//
+// If an error occurs, dst is returned unchanged. If src is nil, ErrNilValue
+// is returned.
+func Append(dst []byte, src Encoder) ([]byte, error) {
+	out, err := appendItem(dst, src)
+	if err != nil {
+		return dst, err
+	}
+	return out, nil
+}
+
+// EncodedSize returns the length of the RLP encoding of the given value.
+//
+// If the value, or any of its list items, implements the Sizer interface, its
+// size is computed without encoding it. Otherwise, the value is encoded to
+// determine its size.
+//
+// Unlike the EncodedSize methods, which return 0 for values that cannot be
+// encoded, this function returns the encoding error. If src is nil,
+// ErrNilValue is returned.
+func EncodedSize(src Encoder) (int, error) {
+	return encodedSize(src)
+}
+
 // Decode decodes RLP item and stores the result in the value pointed to
 // by dst. It returns the number of bytes read and an error, if any.
 //
@@ -95,25 +244,47 @@ const (
 	shortListMax   = 0xf7
 )
 
-// encodeBytes encodes a byte slice into RLP string item.
-func encodeBytes(src []byte) ([]byte, error) {
-	if len(src) == 0 {
-		// Empty string.
-		return []byte{stringOffset}, nil
-	}
+// appendBytes appends the RLP string item encoding of a byte slice to dst.
+func appendBytes(dst []byte, src []byte) ([]byte, error) {
 	if len(src) == 1 && src[0] < stringOffset {
 		// Single byte string in the range [0x00, 0x7F].
-		return []byte{src[0]}, nil
+		return append(dst, src[0]), nil
 	}
-	prefix, err := encodePrefix(uint64(len(src)), stringOffset)
+	dst, err := appendPrefix(dst, uint64(len(src)), stringOffset)
 	if err != nil {
 		return nil, err
 	}
-	return append(prefix, src...), nil
+	return append(dst, src...), nil
 }
 
 // decodeBytes decodes RLP string item into a byte slice.
-func decodeBytes(src []byte, dst *[]byte) (int, error) {
+func decodeBytes(src []byte, dst *[]byte, s *decodeState) (int, error) {
+	n, err := readString(src, dst, s)
+	if err != nil {
+		return 0, decodeError(err, bytesType)
+	}
+	return n, nil
+}
+
+// decodeFixedBytes decodes RLP string item into a byte array. The length of
+// the string must be equal to the length of the array.
+func decodeFixedBytes(src []byte, dst []byte, typ reflect.Type, s *decodeState) (int, error) {
+	var b []byte
+	n, err := readString(src, &b, s)
+	if err != nil {
+		return 0, decodeError(err, typ)
+	}
+	if len(b) != len(dst) {
+		return 0, decodeError(ErrInvalidLength, typ)
+	}
+	copy(dst, b)
+	return n, nil
+}
+
+// readString reads the content of RLP string item. Unlike decodeBytes, it
+// does not wrap errors in a DecodeError, so the caller can do it with the
+// destination type.
+func readString(src []byte, dst *[]byte, s *decodeState) (int, error) {
 	if len(src) == 0 {
 		// The data should not be empty. An empty string is encoded as a single
 		// byte 0x80.
@@ -129,7 +300,7 @@ func decodeBytes(src []byte, dst *[]byte) (int, error) {
 		*dst = src[:1]
 		return 1, nil
 	}
-	offset, dataLen, prefixLen, err := decodePrefix(src)
+	offset, dataLen, prefixLen, err := s.decodePrefix(src)
 	if err != nil {
 		return 0, err
 	}
@@ -144,64 +315,202 @@ func decodeBytes(src []byte, dst *[]byte) (int, error) {
 	return totalLen, nil
 }
 
-// encodeList encodes a slice into an RLP list item.
-func encodeList(src []any) ([]byte, error) {
-	return encodeTypedList(src)
+// appendList appends the RLP list item encoding of a slice to dst.
+func appendList(dst []byte, src []any) ([]byte, error) {
+	return appendTypedList(dst, src)
 }
 
 // decodeList decodes RLP list item into a slice.
-func decodeList(src []byte, dst *[]any) (int, error) {
-	return decodeTypedList(src, dst, func() any { return new(RLP) }, false)
+func decodeList(src []byte, dst *[]any, s *decodeState) (int, error) {
+	return decodeTypedList(src, dst, func() any { return new(RLP) }, false, s)
 }
 
-// encodeTypedList encodes a slice into the RLP list item.
-func encodeTypedList[T any](src []T) ([]byte, error) {
-	var buf bytes.Buffer
-	for _, item := range src {
-		if isNil(item) {
-			return nil, ErrNilValue
+// appendTypedList appends the RLP list item encoding of a slice to dst.
+func appendTypedList[T any](dst []byte, src []T) ([]byte, error) {
+	start := len(dst)
+	dst = append(dst, 0)
+	for _, item := range trimOmitted(src) {
+		var err error
+		if dst, err = appendItem(dst, item); err != nil {
+			return nil, err
 		}
-		switch enc := any(item).(type) {
-		case Encoder:
-			data, err := enc.EncodeRLP()
-			if err != nil {
-				return nil, err
+	}
+	return finishList(dst, start)
+}
+
+// finishList writes the prefix of a list item whose payload has been
+// appended to dst after a single placeholder byte at the start position.
+//
+// The payload size is known only after its items are encoded. Most lists are
+// shorter than 56 bytes and their prefix fits into the placeholder byte, so
+// the payload has to be moved only for longer lists.
+func finishList(dst []byte, start int) ([]byte, error) {
+	payload := len(dst) - start - 1
+	if payload <= 55 {
+		dst[start] = listOffset + byte(payload)
+		return dst, nil
+	}
+	n := intSize(uint64(payload))
+	if n >= 8 {
+		return nil, ErrTooLarge
+	}
+	dst = append(dst, make([]byte, n)...)
+	copy(dst[start+1+n:], dst[start+1:start+1+payload])
+	dst[start] = listOffset + 55 + byte(n)
+	writeInt(dst[start+1:], uint64(payload))
+	return dst, nil
+}
+
+// appendItem appends the RLP encoding of a list item to dst. The item must
+// implement the Encoder interface.
+func appendItem(dst []byte, item any) ([]byte, error) {
+	if isNil(item) {
+		return nil, ErrNilValue
+	}
+	switch v := item.(type) {
+	case Appender:
+		return v.AppendRLP(dst)
+	case Encoder:
+		data, err := v.EncodeRLP()
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, data...), nil
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
+
+// encoderOf returns v as an item that can be passed to appendItem. If T does
+// not implement the Encoder interface, a pointer to a copy of v is returned,
+// so that types whose methods have pointer receivers can be encoded as well.
+func encoderOf[T any](v T) any {
+	if _, ok := any(v).(Encoder); ok {
+		return v
+	}
+	return &v
+}
+
+// decoderOf returns the item that decodes into *p, for use with
+// decodeTypedList. If T is a pointer type that implements the Decoder
+// interface, *p is returned, and if it is nil, a new value is allocated
+// first, in the same way as TypedList creates its items. Otherwise, p is
+// returned.
+func decoderOf[T any](p *T) any {
+	return decoderOfValue(reflect.ValueOf(p).Elem())
+}
+
+// decoderOfValue works like decoderOf for an addressable reflection value.
+func decoderOfValue(v reflect.Value) any {
+	if v.Kind() != reflect.Pointer || !v.Type().Implements(decoderType) {
+		return v.Addr().Interface()
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return v.Interface()
+}
+
+// itemLister is implemented by list types to give access to their items, so
+// that the list can be measured and encoded item by item, without building
+// the list payload in memory first.
+type itemLister interface {
+	// eachItem calls the given function for each item of the list.
+	eachItem(fn func(item any) error) error
+}
+
+// forEachItem calls the given function for each item of the slice.
+// Trailing items that are omitted from the encoding are skipped.
+func forEachItem[T any](src []T, fn func(item any) error) error {
+	for _, item := range trimOmitted(src) {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodedSize returns the length of the RLP encoding of the given item.
+func encodedSize(item any) (int, error) {
+	if isNil(item) {
+		return 0, ErrNilValue
+	}
+	switch v := item.(type) {
+	case itemLister:
+		payload, err := listPayloadSize(v)
+		if err != nil {
+			return 0, err
+		}
+		return prefixSize(uint64(payload)) + payload, nil
+	case RLP:
+		// Raw RLP data must be validated, because the EncodedSize method
+		// returns 0 for invalid data.
+		data, err := v.EncodeRLP()
+		return len(data), err
+	case *RLP:
+		data, err := v.EncodeRLP()
+		return len(data), err
+	case Sizer:
+		// The encoding cannot be empty, so the zero size means that the item
+		// cannot be encoded. The error is returned by the EncodeRLP method.
+		if n := v.EncodedSize(); n > 0 {
+			return n, nil
+		}
+		if e, ok := v.(Encoder); ok {
+			if _, err := e.EncodeRLP(); err != nil {
+				return 0, err
 			}
-			buf.Write(data)
-		default:
-			return nil, ErrUnsupportedType
 		}
+		return 0, ErrUnsupportedType
+	case Encoder:
+		data, err := v.EncodeRLP()
+		if err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	default:
+		return 0, ErrUnsupportedType
 	}
-	prefix, err := encodePrefix(uint64(buf.Len()), listOffset)
+}
+
+// listPayloadSize returns the combined length of the RLP encodings of the
+// list items.
+func listPayloadSize(l itemLister) (int, error) {
+	size := 0
+	err := l.eachItem(func(item any) error {
+		n, err := encodedSize(item)
+		size += n
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return append(prefix, buf.Bytes()...), nil
+	return size, nil
 }
 
 // decodeTypedList decodes items of RLP list item into a slice. Items already
 // present in the slice are decoded into, and, if grow is true, items found
 // after them are appended to the slice. Otherwise, the number of items in the
 // data must match the length of the slice.
-func decodeTypedList[T any](src []byte, dst *[]T, newItem func() T, grow bool) (int, error) {
-	offset, dataLen, prefixLen, err := decodePrefix(src)
+func decodeTypedList[T any](src []byte, dst *[]T, newItem func() T, grow bool, s *decodeState) (int, error) {
+	return decodeTypedListAs(src, dst, newItem, grow, reflect.TypeOf(*dst), s)
+}
+
+// decodeTypedListAs works like decodeTypedList, but errors concerning the
+// whole list are reported with the given type. It is used by types that
+// decode their items through a temporary slice, like Array and the tuples.
+func decodeTypedListAs[T any](src []byte, dst *[]T, newItem func() T, grow bool, typ reflect.Type, s *decodeState) (int, error) {
+	data, totalLen, err := decodeListPayload(src, s)
 	if err != nil {
-		return 0, err
-	}
-	if offset != listOffset {
-		return 0, ErrUnsupportedType
-	}
-	totalLen := int(dataLen + uint64(prefixLen))
-	if len(src) < totalLen {
-		return 0, ErrUnexpectedEndOfData
+		return 0, decodeError(err, typ)
 	}
 	expected := len(*dst)
-	data := src[prefixLen:totalLen]
 	n := 0
 	for ; len(data) > 0; n++ {
+		offset := totalLen - len(data)
 		if !grow && n >= expected {
 			// The data contains more items than expected.
-			return 0, ErrUnexpectedNumberOfItems
+			return 0, itemError(ErrUnexpectedNumberOfItems, n, offset, nil)
 		}
 		// Reuse the item already in the destination slice, if any.
 		// Otherwise, create a new one. Nil items are replaced with new ones to
@@ -215,16 +524,18 @@ func decodeTypedList[T any](src []byte, dst *[]T, newItem func() T, grow bool) (
 		}
 		dec, ok := any(item).(Decoder)
 		if !ok {
-			return 0, ErrUnsupportedType
+			return 0, itemError(ErrUnsupportedType, n, offset, reflect.TypeOf(item))
 		}
-		itemLen, err := dec.DecodeRLP(data)
+		s.enter(n)
+		itemLen, err := decodeItem(dec, data, s)
+		s.leave()
 		if err != nil {
-			return 0, err
+			return 0, itemError(err, n, offset, reflect.TypeOf(item))
 		}
 		if itemLen <= 0 || itemLen > len(data) {
 			// The item must not be empty, otherwise the loop would never end,
 			// and it must not exceed the list payload.
-			return 0, ErrUnexpectedEndOfData
+			return 0, itemError(ErrUnexpectedEndOfData, n, offset, reflect.TypeOf(item))
 		}
 		switch {
 		case reuse:
@@ -237,9 +548,18 @@ func decodeTypedList[T any](src []byte, dst *[]T, newItem func() T, grow bool) (
 		}
 		data = data[itemLen:]
 	}
-	if !grow && n < expected {
-		// The data contains fewer items than expected.
-		return 0, ErrUnexpectedNumberOfItems
+	for ; !grow && n < expected; n++ {
+		// The data contains fewer items than expected, which is allowed only
+		// if the missing items can be omitted.
+		item := (*dst)[n]
+		if isNil(item) {
+			item = newItem()
+		}
+		o, ok := any(item).(omittableDecoder)
+		if !ok || !o.decodeOmitted() {
+			return 0, decodeError(ErrUnexpectedNumberOfItems, typ)
+		}
+		(*dst)[n] = item
 	}
 	if n == 0 {
 		// The data is an empty list.
@@ -248,100 +568,362 @@ func decodeTypedList[T any](src []byte, dst *[]T, newItem func() T, grow bool) (
 	return totalLen, nil
 }
 
-// encodeString encodes a Go string into RLP string item.
-func encodeString(src string) ([]byte, error) {
-	return encodeBytes([]byte(src))
+// decodeListPayload decodes the prefix of an RLP list item and returns the
+// list payload and the total length of the item.
+func decodeListPayload(src []byte, s *decodeState) ([]byte, int, error) {
+	offset, dataLen, prefixLen, err := s.decodePrefix(src)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset != listOffset {
+		return nil, 0, ErrUnsupportedType
+	}
+	totalLen := int(dataLen + uint64(prefixLen))
+	if len(src) < totalLen {
+		return nil, 0, ErrUnexpectedEndOfData
+	}
+	return src[prefixLen:totalLen], totalLen, nil
+}
+
+// appendString appends the RLP string item encoding of a Go string to dst.
+func appendString(dst []byte, src string) ([]byte, error) {
+	if len(src) == 1 && src[0] < stringOffset {
+		// Single byte string in the range [0x00, 0x7F].
+		return append(dst, src[0]), nil
+	}
+	dst, err := appendPrefix(dst, uint64(len(src)), stringOffset)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, src...), nil
 }
 
 // decodeString decodes RLP string item into a Go string.
-func decodeString(src []byte, dst *string) (int, error) {
+func decodeString(src []byte, dst *string, s *decodeState) (int, error) {
 	var b []byte
-	i, err := decodeBytes(src, &b)
+	i, err := readString(src, &b, s)
 	if err != nil {
-		return 0, err
+		return 0, decodeError(err, stringType)
 	}
 	*dst = string(b)
 	return i, nil
 }
 
-// encodeUint encodes an Go unsigned integer into RLP integer item.
-func encodeUint(src uint64) ([]byte, error) {
+// appendUint appends the RLP integer item encoding of a Go unsigned integer
+// to dst.
+func appendUint(dst []byte, src uint64) ([]byte, error) {
 	if src == 0 {
 		// For zero values, the RLP encoding is a zero-length string.
-		return []byte{stringOffset}, nil
+		return append(dst, stringOffset), nil
+	}
+	if src < stringOffset {
+		// Single byte in the range [0x01, 0x7F].
+		return append(dst, byte(src)), nil
 	}
-	d := make([]byte, 8)
-	l := writeInt(d, src)
-	return encodeBytes(d[:l])
+	var b [8]byte
+	l := writeInt(b[:], src)
+	dst = append(dst, stringOffset+byte(l))
+	return append(dst, b[:l]...), nil
 }
 
 // decodeUint decodes RLP integer item into a Go unsigned integer.
-func decodeUint(src []byte, dst *uint64) (int, error) {
-	var b []byte
-	i, err := decodeBytes(src, &b)
+func decodeUint(src []byte, dst *uint64, s *decodeState) (int, error) {
+	n, i, err := readUint(src, 8, uint64Type, s)
 	if err != nil {
 		return 0, err
 	}
-	if len(b) > 8 {
-		return 0, ErrTooLarge
+	*dst = n
+	return i, nil
+}
+
+// readUint reads RLP integer item whose value fits into the given number of
+// bytes. It returns the value and the length of the item. Errors are wrapped
+// in a DecodeError with the given type.
+func readUint(src []byte, size int, typ reflect.Type, s *decodeState) (uint64, int, error) {
+	var b []byte
+	i, err := readString(src, &b, s)
+	if err != nil {
+		return 0, 0, decodeError(err, typ)
 	}
-	if err := verifyCanonicalInt(b); err != nil {
-		return 0, err
+	if b, err = s.canonicalUint(src, b, size, typ); err != nil {
+		return 0, 0, decodeError(err, typ)
 	}
 	n, err := readInt(b, uint8(len(b)))
+	if err != nil {
+		return 0, 0, decodeError(err, typ)
+	}
+	return n, i, nil
+}
+
+// zigzag maps a signed integer to an unsigned one, so that integers with a
+// small absolute value are mapped to small unsigned integers: 0, -1, 1, -2
+// are mapped to 0, 1, 2, 3.
+func zigzag(i int64) uint64 {
+	return uint64(i<<1) ^ uint64(i>>63)
+}
+
+// unzigzag reverses the mapping done by zigzag.
+func unzigzag(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// twosComplement writes the minimal big endian two's complement
+// representation of a signed integer to b and returns the number of bytes
+// written. Zero is represented by zero bytes.
+func twosComplement(b *[8]byte, i int64) int {
+	if i == 0 {
+		return 0
+	}
+	n := 8
+	for n > 1 {
+		// The most significant byte can be dropped if it only extends the
+		// sign of the next byte.
+		top, next := byte(i>>(8*(n-1))), byte(i>>(8*(n-2)))
+		if (top != 0x00 || next >= 0x80) && (top != 0xff || next < 0x80) {
+			break
+		}
+		n--
+	}
+	for j := 0; j < n; j++ {
+		b[j] = byte(i >> (8 * (n - 1 - j)))
+	}
+	return n
+}
+
+// appendTwosComplementInt appends the RLP string item encoding of the
+// minimal two's complement representation of a signed integer to dst.
+func appendTwosComplementInt(dst []byte, src int64) ([]byte, error) {
+	var b [8]byte
+	return appendBytes(dst, b[:twosComplement(&b, src)])
+}
+
+// decodeTwosComplementInt decodes RLP string item holding the minimal two's
+// complement representation of a signed integer.
+func decodeTwosComplementInt(src []byte, dst *int64, s *decodeState) (int, error) {
+	var b []byte
+	n, err := readString(src, &b, s)
+	if err != nil {
+		return 0, decodeError(err, int64Type)
+	}
+	if len(b) > 8 {
+		return 0, decodeError(ErrTooLarge, int64Type)
+	}
+	var buf [8]byte
+	if len(b) > 0 && twosComplement(&buf, signExtend(b)) != len(b) {
+		// The representation is not minimal.
+		if err := s.nonCanonical(src, int64Type); err != nil {
+			return 0, decodeError(err, int64Type)
+		}
+	}
+	*dst = signExtend(b)
+	return n, nil
+}
+
+// signExtend converts a big endian two's complement representation of at
+// most 8 bytes to a signed integer.
+func signExtend(b []byte) int64 {
+	if len(b) == 0 {
+		return 0
+	}
+	u, _ := readInt(b, uint8(len(b)))
+	if shift := 64 - 8*len(b); shift > 0 {
+		return int64(u<<shift) >> shift
+	}
+	return int64(u)
+}
+
+// appendBool appends the RLP encoding of a Go boolean to dst. False is
+// encoded as an empty string and true as a single byte 0x01.
+func appendBool(dst []byte, src bool) ([]byte, error) {
+	if src {
+		return append(dst, 0x01), nil
+	}
+	return append(dst, stringOffset), nil
+}
+
+// decodeBool decodes RLP item into a Go boolean. Only the encodings produced
+// by appendBool are accepted, unless the lenient mode is enabled, in which
+// case other encodings of the integers 0 and 1 are accepted as well.
+func decodeBool(src []byte, dst *bool, s *decodeState) (int, error) {
+	if len(src) == 0 {
+		return 0, decodeError(ErrUnexpectedEndOfData, boolType)
+	}
+	switch src[0] {
+	case stringOffset:
+		*dst = false
+		return 1, nil
+	case 0x01:
+		*dst = true
+		return 1, nil
+	}
+	if !s.lenient() {
+		return 0, decodeError(ErrInvalidBool, boolType)
+	}
+	n, i, err := readUint(src, 8, boolType, s)
 	if err != nil {
 		return 0, err
 	}
-	*dst = n
+	if n > 1 {
+		return 0, decodeError(ErrInvalidBool, boolType)
+	}
+	*dst = n == 1
 	return i, nil
 }
 
-// encodeBigInt encodes a Go big integer into an RLP integer item.
-func encodeBigInt(src *big.Int) ([]byte, error) {
+// appendBigInt appends the RLP integer item encoding of a Go big integer to
+// dst.
+//
+// RLP integers are unsigned, so negative values are rejected with
+// ErrNegativeValue.
+func appendBigInt(dst []byte, src *big.Int) ([]byte, error) {
+	if src.Sign() < 0 {
+		return nil, ErrNegativeValue
+	}
 	if src.Sign() == 0 {
 		// For zero values, the RLP encoding is a zero-length string.
-		return []byte{stringOffset}, nil
+		return append(dst, stringOffset), nil
 	}
-	return encodeBytes(src.Bytes())
+	l := (src.BitLen() + 7) / 8
+	if l == 1 && src.Uint64() < stringOffset {
+		// Single byte in the range [0x01, 0x7F].
+		return append(dst, byte(src.Uint64())), nil
+	}
+	dst, err := appendPrefix(dst, uint64(l), stringOffset)
+	if err != nil {
+		return nil, err
+	}
+	dst = append(dst, make([]byte, l)...)
+	src.FillBytes(dst[len(dst)-l:])
+	return dst, nil
 }
 
 // decodeBigInt decodes RLP integer item into a Go big integer.
-func decodeBigInt(src []byte, dst *big.Int) (int, error) {
+func decodeBigInt(src []byte, dst *big.Int, s *decodeState) (int, error) {
 	var b []byte
-	i, err := decodeBytes(src, &b)
+	i, err := readString(src, &b, s)
 	if err != nil {
-		return 0, err
+		return 0, decodeError(err, bigIntType)
 	}
-	if err := verifyCanonicalInt(b); err != nil {
-		return 0, err
+	if b, err = s.canonicalInt(src, b, bigIntType); err != nil {
+		return 0, decodeError(err, bigIntType)
 	}
 	dst.SetBytes(b)
 	return i, nil
 }
 
-// encodePrefix encodes the RLP prefix for given offset and length. The offset
-// value must be either stringOffset or listOffset.
-func encodePrefix(length uint64, offset byte) ([]byte, error) {
+// appendSignedBigInt appends the RLP integer item encoding of a signed Go big
+// integer to dst, using the zigzag encoding. Non-negative values are encoded
+// as 2*x and negative values as -2*x-1.
+func appendSignedBigInt(dst []byte, src *big.Int) ([]byte, error) {
+	z := new(big.Int).Lsh(src, 1)
+	if src.Sign() < 0 {
+		z.Neg(z).Sub(z, big.NewInt(1))
+	}
+	return appendBigInt(dst, z)
+}
+
+// decodeSignedBigInt decodes RLP integer item encoded using the zigzag
+// encoding into a signed Go big integer.
+func decodeSignedBigInt(src []byte, dst *big.Int, s *decodeState) (int, error) {
+	n, err := decodeBigInt(src, dst, s)
+	if err != nil {
+		return 0, err
+	}
+	if dst.Bit(0) == 0 {
+		dst.Rsh(dst, 1)
+	} else {
+		dst.Add(dst, big.NewInt(1)).Rsh(dst, 1).Neg(dst)
+	}
+	return n, nil
+}
+
+// signedBigIntSize returns the length of the RLP encoding of a signed Go big
+// integer, that is, the number of bytes appended by appendSignedBigInt.
+func signedBigIntSize(src *big.Int) int {
+	z := new(big.Int).Lsh(src, 1)
+	if src.Sign() < 0 {
+		z.Neg(z).Sub(z, big.NewInt(1))
+	}
+	return bigIntSize(z)
+}
+
+// appendPrefix appends the RLP prefix for given offset and length to dst.
+// The offset value must be either stringOffset or listOffset.
+func appendPrefix(dst []byte, length uint64, offset byte) ([]byte, error) {
 	// For length 0-55, the RLP encoding consists of a single byte with value
 	// stringOffset or listOffset plus the length of the data.
 	if length <= 55 {
-		return []byte{offset + byte(length)}, nil
+		return append(dst, offset+byte(length)), nil
 	}
 	// For longer data, the RLP encoding consists of a single byte with value
 	// stringOffset or listOffset plus 55 and plus number of bytes required to
 	// represent the length of the data.
-	prefix := make([]byte, 9)
-	bytesLen := writeInt(prefix[1:], length)
+	var b [8]byte
+	bytesLen := writeInt(b[:], length)
 	if bytesLen >= 8 {
 		return nil, ErrTooLarge
 	}
-	prefix[0] = offset + byte(bytesLen) + 55
-	return prefix[:bytesLen+1], nil
+	dst = append(dst, offset+byte(bytesLen)+55)
+	return append(dst, b[:bytesLen]...), nil
+}
+
+// prefixSize returns the length of the RLP prefix for data of the given
+// length. It uses the same rules as appendPrefix.
+func prefixSize(length uint64) int {
+	if length <= 55 {
+		return 1
+	}
+	return 1 + intSize(length)
+}
+
+// bytesSize returns the length of the RLP encoding of the given byte slice.
+func bytesSize(src []byte) int {
+	if len(src) == 1 && src[0] < stringOffset {
+		return 1
+	}
+	return prefixSize(uint64(len(src))) + len(src)
+}
+
+// stringSize returns the length of the RLP encoding of the given Go string.
+func stringSize(src string) int {
+	if len(src) == 1 && src[0] < stringOffset {
+		return 1
+	}
+	return prefixSize(uint64(len(src))) + len(src)
+}
+
+// uintSize returns the length of the RLP encoding of the given unsigned
+// integer.
+func uintSize(src uint64) int {
+	if src < stringOffset {
+		// Zero is encoded as an empty string, and integers in the range
+		// [0x01, 0x7F] are encoded as a single byte.
+		return 1
+	}
+	return 1 + intSize(src)
+}
+
+// bigIntSize returns the length of the RLP encoding of the given big integer.
+func bigIntSize(src *big.Int) int {
+	if src.Sign() == 0 {
+		return 1
+	}
+	n := (src.BitLen() + 7) / 8
+	if n == 1 && src.Uint64() < stringOffset {
+		return 1
+	}
+	return prefixSize(uint64(n)) + n
 }
 
 // decodePrefix decodes RLP prefix and returns offset, data length, and prefix
 // length. Any data after the prefix is ignored.
 func decodePrefix(prefix []byte) (offset byte, dataLen uint64, prefixLen uint8, err error) {
+	return parsePrefix(prefix, true)
+}
+
+// parsePrefix works like decodePrefix, but if canonical is false, it accepts
+// prefixes that are not encoded in their canonical form.
+func parsePrefix(prefix []byte, canonical bool) (offset byte, dataLen uint64, prefixLen uint8, err error) {
 	if len(prefix) == 0 {
 		return 0, 0, 0, ErrUnexpectedEndOfData
 	}
@@ -359,7 +941,7 @@ func decodePrefix(prefix []byte) (offset byte, dataLen uint64, prefixLen uint8,
 		offset = stringOffset
 		dataLen = uint64(cur - stringOffset)
 		prefixLen = 1
-		if dataLen == 1 {
+		if dataLen == 1 && canonical {
 			// A single byte in the [0x00, 0x7F] range must be encoded as
 			// itself, without the prefix.
 			if len(prefix) < 2 {
@@ -382,8 +964,10 @@ func decodePrefix(prefix []byte) (offset byte, dataLen uint64, prefixLen uint8,
 		if err != nil {
 			return 0, 0, 0, err
 		}
-		if err := verifyCanonicalLength(prefix[1:], dataLen); err != nil {
-			return 0, 0, 0, err
+		if canonical {
+			if err := verifyCanonicalLength(prefix[1:], dataLen); err != nil {
+				return 0, 0, 0, err
+			}
 		}
 		offset = stringOffset
 		prefixLen = 1 + bytesLen
@@ -410,8 +994,10 @@ func decodePrefix(prefix []byte) (offset byte, dataLen uint64, prefixLen uint8,
 		if err != nil {
 			return 0, 0, 0, err
 		}
-		if err := verifyCanonicalLength(prefix[1:], dataLen); err != nil {
-			return 0, 0, 0, err
+		if canonical {
+			if err := verifyCanonicalLength(prefix[1:], dataLen); err != nil {
+				return 0, 0, 0, err
+			}
 		}
 		offset = listOffset
 		prefixLen = 1 + bytesLen
@@ -460,6 +1046,18 @@ func isNil(v any) bool {
 	}
 }
 
+// intSize returns the number of bytes required to represent the given
+// integer in big endian order, that is, the number of bytes written by
+// writeInt.
+func intSize(i uint64) int {
+	n := 1
+	for i >= 1<<8 {
+		i >>= 8
+		n++
+	}
+	return n
+}
+
 // writeInt writes an integer to the given buffer in big endian order.
 // The number of bytes written is returned.
 func writeInt(b []byte, i uint64) int {
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
@@ -309,6 +311,245 @@ func TestDecodeStream(t *testing.T) {
 	}
 }
 
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		data    Encoder
+		wantErr bool
+	}{
+		{data: String("")},
+		{data: String("a")},
+		{data: String(strings.Repeat("a", 56))},
+		{data: Bytes{0x80}},
+		{data: Uint(0)},
+		{data: Uint(127)},
+		{data: Uint(math.MaxUint64)},
+		{data: (*BigInt)(big.NewInt(0))},
+		{data: (*BigInt)(big.NewInt(127))},
+		{data: (*BigInt)(new(big.Int).Lsh(big.NewInt(1), 256))},
+		{data: RLP{0x83, 'd', 'o', 'g'}},
+		{data: List{}},
+		{data: List{String("dog"), List{Uint(1)}}},
+		{data: List(makeSlice(56, String("a")))},
+		{data: List{List(makeSlice(256, String("a"))), List(makeSlice(56, String("b")))}},
+		{data: TypedList[String]{ptr(String("dog"))}},
+		{data: VarList{String("dog"), testList{String("cat")}}},
+		{data: VarTypedList[Uint]{ptr(Uint(1024))}},
+		{data: List{nil}, wantErr: true},
+		{data: List{1}, wantErr: true},
+		{data: List{String("a"), errItem{}}, wantErr: true},
+		{data: RLP{0x81}, wantErr: true},
+		{data: (*Uint)(nil), wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			dst := []byte{0x01, 0x02}
+			got, err := Append(dst, tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Append() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !bytes.Equal(got, dst) {
+					t.Fatalf("Append() got = %x, want unchanged %x", got, dst)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Append() unexpected error = %v", err)
+			}
+			want, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("Encode() unexpected error = %v", err)
+			}
+			if !bytes.Equal(got, append([]byte{0x01, 0x02}, want...)) {
+				t.Fatalf("Append() got = %x, want %x", got, append([]byte{0x01, 0x02}, want...))
+			}
+		})
+	}
+}
+
+func TestAppendAllocs(t *testing.T) {
+	str, num, bi := String("dog"), Uint(1024), (*BigInt)(new(big.Int).Lsh(big.NewInt(1), 100))
+	list := TypedList[String]{&str, &str}
+	item := List{&str, &num, bi, &list}
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		var err error
+		if buf, err = Append(buf[:0], &item); err != nil {
+			t.Fatalf("Append() failed: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestEncodedSize(t *testing.T) {
+	tests := []struct {
+		data    Encoder
+		wantErr bool
+	}{
+		{data: String("")},
+		{data: String("a")},
+		{data: String("\x80")},
+		{data: String(strings.Repeat("a", 55))},
+		{data: String(strings.Repeat("a", 56))},
+		{data: String(strings.Repeat("a", 256))},
+		{data: Bytes{}},
+		{data: Bytes{0x7f}},
+		{data: Uint(0)},
+		{data: Uint(127)},
+		{data: Uint(128)},
+		{data: Uint(math.MaxUint64)},
+		{data: (*BigInt)(big.NewInt(0))},
+		{data: (*BigInt)(big.NewInt(127))},
+		{data: (*BigInt)(big.NewInt(128))},
+		{data: (*BigInt)(new(big.Int).Lsh(big.NewInt(1), 500))},
+		{data: RLP{0x83, 'd', 'o', 'g', 0x80}},
+		{data: &RLP{0xc1, 0x80}},
+		{data: List{}},
+		{data: List{String("dog"), List{Uint(1)}, RLP{0x80}}},
+		{data: List(makeSlice(56, String("a")))},
+		{data: List{List(makeSlice(256, String("a")))}},
+		{data: TypedList[String]{ptr(String("dog"))}},
+		{data: VarList{String("dog"), testList{String("cat")}}},
+		{data: VarTypedList[Uint]{ptr(Uint(1024))}},
+		{data: List{nil}, wantErr: true},
+		{data: List{1}, wantErr: true},
+		{data: List{errItem{}}, wantErr: true},
+		{data: RLP{0x81}, wantErr: true},
+		{data: &RLP{}, wantErr: true},
+		{data: List{RLP{0x81}}, wantErr: true},
+		{data: (*Uint)(nil), wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := EncodedSize(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("EncodedSize() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if s, ok := tt.data.(Sizer); ok && !isNil(s) && s.EncodedSize() != 0 {
+					t.Fatalf("EncodedSize() method = %v, want 0", s.EncodedSize())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EncodedSize() unexpected error = %v", err)
+			}
+			enc, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("Encode() unexpected error = %v", err)
+			}
+			if got != len(enc) {
+				t.Fatalf("EncodedSize() got = %v, want %v", got, len(enc))
+			}
+			if s, ok := tt.data.(Sizer); ok && s.EncodedSize() != len(enc) {
+				t.Fatalf("EncodedSize() method = %v, want %v", s.EncodedSize(), len(enc))
+			}
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		data       []byte
+		dest       Decoder
+		wantErr    error
+		wantOffset int
+		wantPath   string
+		wantType   reflect.Type
+	}{
+		{
+			data:       []byte{0x82, 0x00, 0x01},
+			dest:       new(Uint),
+			wantErr:    ErrNonCanonicalEncoding,
+			wantOffset: 0,
+			wantPath:   "",
+			wantType:   reflect.TypeOf(uint64(0)),
+		},
+		{
+			// [0x80, ["a", ["dog", 0x0001]]]
+			data:       []byte{0xcb, 0x80, 0xc9, 0x61, 0xc7, 0x83, 'd', 'o', 'g', 0x82, 0x00, 0x01},
+			dest:       ptr(List{new(String), ptr(List{new(String), ptr(TypedList[Uint]{nil, nil})})}),
+			wantErr:    ErrNonCanonicalEncoding,
+			wantOffset: 9,
+			wantPath:   "[1][1][1]",
+			wantType:   reflect.TypeOf(uint64(0)),
+		},
+		{
+			data:       []byte{0xc3, 0x80, 0xc1, 0x80},
+			dest:       ptr(List{new(String), new(String)}),
+			wantErr:    ErrUnsupportedType,
+			wantOffset: 2,
+			wantPath:   "[1]",
+			wantType:   reflect.TypeOf(""),
+		},
+		{
+			data:       []byte{0xc2, 0x80, 0x80},
+			dest:       ptr(List{new(String)}),
+			wantErr:    ErrUnexpectedNumberOfItems,
+			wantOffset: 2,
+			wantPath:   "[1]",
+		},
+		{
+			data:       []byte{0xc3, 0x80, 0xc1, 0x80},
+			dest:       ptr(List{new(String), ptr(List{})}),
+			wantErr:    ErrUnexpectedNumberOfItems,
+			wantOffset: 3,
+			wantPath:   "[1][0]",
+		},
+		{
+			data:       []byte{0xc2, 0x80, 0x81},
+			dest:       ptr(List{new(String), new(RLP)}),
+			wantErr:    ErrUnexpectedEndOfData,
+			wantOffset: 2,
+			wantPath:   "[1]",
+			wantType:   reflect.TypeOf(RLP{}),
+		},
+		{
+			data:       []byte{0xc2, 0x80, 0x80},
+			dest:       ptr(List{new(String), errItem{}}),
+			wantOffset: 2,
+			wantPath:   "[1]",
+			wantType:   reflect.TypeOf(errItem{}),
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			_, err := Decode(tt.data, tt.dest)
+			var decErr *DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if decErr.Offset != tt.wantOffset {
+				t.Errorf("expected offset %v, got %v", tt.wantOffset, decErr.Offset)
+			}
+			if decErr.PathString() != tt.wantPath {
+				t.Errorf("expected path %q, got %q", tt.wantPath, decErr.PathString())
+			}
+			if tt.wantType != nil && decErr.Type != tt.wantType {
+				t.Errorf("expected type %v, got %v", tt.wantType, decErr.Type)
+			}
+		})
+	}
+}
+
+func TestDecodeErrorMessage(t *testing.T) {
+	err := &DecodeError{
+		Offset: 9,
+		Path:   []int{3, 0, 8},
+		Type:   reflect.TypeOf(uint64(0)),
+		Err:    ErrUnexpectedEndOfData,
+	}
+	want := "rlp: unexpected end of data (offset 9, path [3][0][8], type uint64)"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+}
+
 func FuzzDecode(f *testing.F) {
 	for _, s := range [][]byte{
 		{stringOffset},
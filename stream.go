@@ -0,0 +1,299 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	ErrEndOfList = errors.New("rlp: end of list")
+	ErrNotInList = errors.New("rlp: not in list")
+)
+
+// Kind represents the kind of an RLP item.
+type Kind int
+
+const (
+	KindString Kind = iota // RLP string, including single byte strings.
+	KindList               // RLP list.
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindList:
+		return "list"
+	default:
+		return "unknown"
+	}
+}
+
+// Stream reads RLP items from an io.Reader incrementally.
+//
+// The Stream reads items one by one, so the data does not have to be loaded
+// into memory in advance. Lists are entered using the List method, then
+// their items are read, and finally the lists are left using the ListEnd
+// method.
+//
+// The size of each item is verified against the input limit and the size of
+// the enclosing list before its payload is read, so malformed data that
+// declares a large item cannot cause a large allocation.
+//
+// After an error is returned, the Stream must not be used anymore, except for
+// io.EOF and ErrEndOfList errors returned by the Kind method.
+type Stream struct {
+	r       io.Reader
+	limited bool     // Whether the input limit is set.
+	limit   uint64   // Number of bytes that can still be read from the input.
+	stack   []uint64 // Remaining payload sizes of the entered lists.
+
+	// The header of the current item, read by the Kind method or by any other
+	// method that reads an item.
+	hasHdr    bool
+	hdr       [10]byte // Prefix and the first payload bytes, if already read.
+	hdrLen    int      // Number of bytes in hdr.
+	prefixLen int      // Length of the prefix.
+	kind      Kind     // Kind of the item.
+	size      uint64   // Payload size of the item.
+}
+
+// NewStream returns a new Stream that reads from r.
+//
+// The limit is the maximum number of bytes that can be read from r. Items
+// whose declared size exceeds the limit are rejected with ErrTooLarge. If the
+// limit is 0 and r is a *bytes.Reader, *strings.Reader or *bytes.Buffer, the
+// limit is set to the number of unread bytes in r. Otherwise, the input size
+// is not limited, in which case only the sizes of the enclosing lists limit
+// the sizes of items, and large payloads are read in chunks, so that data
+// declaring a huge item cannot cause a huge allocation.
+func NewStream(r io.Reader, limit uint64) *Stream {
+	if limit == 0 {
+		switch br := r.(type) {
+		case *bytes.Reader:
+			return &Stream{r: r, limited: true, limit: uint64(br.Len())}
+		case *strings.Reader:
+			return &Stream{r: r, limited: true, limit: uint64(br.Len())}
+		case *bytes.Buffer:
+			return &Stream{r: r, limited: true, limit: uint64(br.Len())}
+		}
+	}
+	return &Stream{r: r, limited: limit > 0, limit: limit}
+}
+
+// Kind reads the header of the next item and returns its kind and payload
+// size. The item is not consumed, so it can be read by any other method
+// afterwards.
+//
+// If there are no more items in the current list, ErrEndOfList is returned.
+// If there are no more items in the input, io.EOF is returned.
+func (s *Stream) Kind() (Kind, uint64, error) {
+	if err := s.readHeader(); err != nil {
+		return 0, 0, err
+	}
+	return s.kind, s.size, nil
+}
+
+// List enters the next item, which must be a list, and returns its payload
+// size. The items of the list can be read afterwards, and ListEnd must be
+// called after the last item.
+func (s *Stream) List() (uint64, error) {
+	if err := s.readHeader(); err != nil {
+		return 0, err
+	}
+	if s.kind != KindList {
+		return 0, ErrUnsupportedType
+	}
+	s.hasHdr = false
+	s.stack = append(s.stack, s.size)
+	return s.size, nil
+}
+
+// ListEnd leaves the current list. All items of the list must have been
+// read, otherwise ErrUnexpectedNumberOfItems is returned.
+func (s *Stream) ListEnd() error {
+	if len(s.stack) == 0 {
+		return ErrNotInList
+	}
+	if s.hasHdr || s.stack[len(s.stack)-1] > 0 {
+		return ErrUnexpectedNumberOfItems
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	return nil
+}
+
+// Bytes reads the next item, which must be a string, and returns its
+// content.
+func (s *Stream) Bytes() ([]byte, error) {
+	if err := s.readHeader(); err != nil {
+		return nil, err
+	}
+	if s.kind != KindString {
+		return nil, ErrUnsupportedType
+	}
+	return s.readItem(make([]byte, 0, s.capHint(0)))
+}
+
+// Uint reads the next item, which must be an RLP integer, and returns its
+// value. The same rules as for the Uint type apply.
+func (s *Stream) Uint() (uint64, error) {
+	if err := s.readHeader(); err != nil {
+		return 0, err
+	}
+	if s.kind != KindString {
+		return 0, ErrUnsupportedType
+	}
+	if s.size > 8 {
+		return 0, ErrTooLarge
+	}
+	var buf [8]byte
+	b := buf[:s.size]
+	if err := s.readPayload(b); err != nil {
+		return 0, err
+	}
+	if err := verifyCanonicalInt(b); err != nil {
+		return 0, err
+	}
+	return readInt(b, uint8(len(b)))
+}
+
+// Raw reads the next item and returns its whole RLP encoding, including the
+// prefix. The returned value may be decoded further using the Decode
+// function or the RLP type.
+func (s *Stream) Raw() ([]byte, error) {
+	if err := s.readHeader(); err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, s.capHint(s.prefixLen))
+	return s.readItem(append(b, s.hdr[:s.prefixLen]...))
+}
+
+// readHeader reads the header of the next item, unless it is already read.
+func (s *Stream) readHeader() error {
+	if s.hasHdr {
+		return nil
+	}
+	if len(s.stack) > 0 && s.stack[len(s.stack)-1] == 0 {
+		return ErrEndOfList
+	}
+	if len(s.stack) == 0 && s.limited && s.limit == 0 {
+		return io.EOF
+	}
+	if err := s.read(s.hdr[:1]); err != nil {
+		if errors.Is(err, ErrUnexpectedEndOfData) && len(s.stack) == 0 {
+			// There are no more items in the input.
+			return io.EOF
+		}
+		return err
+	}
+	// Read the rest of the prefix. For a string of length 1, the payload
+	// byte is read as well, because it is required to verify that the
+	// string is encoded canonically.
+	n := 1
+	switch b := s.hdr[0]; {
+	case b == stringOffset+1:
+		n = 2
+	case b > shortStringMax && b <= longStringMax && b-shortStringMax < 8:
+		n = 1 + int(b-shortStringMax)
+	case b > shortListMax && b-shortListMax < 8:
+		n = 1 + int(b-shortListMax)
+	}
+	if err := s.read(s.hdr[1:n]); err != nil {
+		return err
+	}
+	offset, dataLen, prefixLen, err := decodePrefix(s.hdr[:n])
+	if err != nil {
+		return err
+	}
+	totalLen := dataLen + uint64(prefixLen)
+	if len(s.stack) > 0 {
+		// The item must fit into the enclosing list.
+		if totalLen > s.stack[len(s.stack)-1] {
+			return ErrUnexpectedEndOfData
+		}
+		s.stack[len(s.stack)-1] -= totalLen
+	}
+	if s.limited && totalLen-uint64(n) > s.limit {
+		// The item is larger than the remaining input.
+		return ErrTooLarge
+	}
+	s.hasHdr = true
+	s.hdrLen = n
+	s.prefixLen = int(prefixLen)
+	s.size = dataLen
+	s.kind = KindString
+	if offset == listOffset {
+		s.kind = KindList
+	}
+	return nil
+}
+
+// maxPrealloc is the maximum payload size that is allocated in advance if the
+// input size is not limited. Larger payloads are read in chunks of this size.
+const maxPrealloc = 64 * 1024
+
+// capHint returns the capacity of a buffer for the payload of the current
+// item preceded by n bytes.
+func (s *Stream) capHint(n int) int {
+	if !s.limited && s.size > maxPrealloc {
+		return n + maxPrealloc
+	}
+	return n + int(s.size)
+}
+
+// readItem reads the payload of the current item, appends it to dst and
+// consumes the item.
+//
+// The declared payload size is verified against the input limit by
+// readHeader, but it cannot be verified if the input size is not limited. In
+// that case, the payload is read in chunks, so the buffer grows only as the
+// data is actually read.
+func (s *Stream) readItem(dst []byte) ([]byte, error) {
+	s.hasHdr = false
+	dst = append(dst, s.hdr[s.prefixLen:s.hdrLen]...)
+	remaining := s.size - uint64(s.hdrLen-s.prefixLen)
+	for remaining > 0 {
+		n := remaining
+		if n > maxPrealloc {
+			n = maxPrealloc
+		}
+		start := len(dst)
+		dst = append(dst, make([]byte, n)...)
+		if err := s.read(dst[start:]); err != nil {
+			return nil, err
+		}
+		remaining -= n
+	}
+	return dst, nil
+}
+
+// readPayload reads the payload of the current item into b, which must have
+// the length of the payload, and consumes the item.
+func (s *Stream) readPayload(b []byte) error {
+	s.hasHdr = false
+	n := copy(b, s.hdr[s.prefixLen:s.hdrLen])
+	return s.read(b[n:])
+}
+
+// read reads exactly len(b) bytes from the input.
+func (s *Stream) read(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if s.limited {
+		if uint64(len(b)) > s.limit {
+			return ErrUnexpectedEndOfData
+		}
+		s.limit -= uint64(len(b))
+	}
+	if _, err := io.ReadFull(s.r, b); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrUnexpectedEndOfData
+		}
+		return err
+	}
+	return nil
+}
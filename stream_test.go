@@ -0,0 +1,346 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	// [["dog", 1024], "", 0x05, [[]]]
+	data := []byte{0xcc, 0xc7, 0x83, 'd', 'o', 'g', 0x82, 0x04, 0x00, 0x80, 0x05, 0xc1, 0xc0}
+	s := NewStream(bytes.NewReader(data), uint64(len(data)))
+	expectKind(t, s, KindList, 12)
+	expectList(t, s, 12)
+	expectList(t, s, 7)
+	expectKind(t, s, KindString, 3)
+	b, err := s.Bytes()
+	if err != nil || string(b) != "dog" {
+		t.Fatalf("Bytes() = %q, %v, want %q", b, err, "dog")
+	}
+	u, err := s.Uint()
+	if err != nil || u != 1024 {
+		t.Fatalf("Uint() = %v, %v, want %v", u, err, 1024)
+	}
+	if _, _, err := s.Kind(); !errors.Is(err, ErrEndOfList) {
+		t.Fatalf("Kind() error = %v, want ErrEndOfList", err)
+	}
+	if err := s.ListEnd(); err != nil {
+		t.Fatalf("ListEnd() failed: %v", err)
+	}
+	b, err = s.Bytes()
+	if err != nil || len(b) != 0 {
+		t.Fatalf("Bytes() = %x, %v, want empty", b, err)
+	}
+	raw, err := s.Raw()
+	if err != nil || !bytes.Equal(raw, []byte{0x05}) {
+		t.Fatalf("Raw() = %x, %v, want 05", raw, err)
+	}
+	raw, err = s.Raw()
+	if err != nil || !bytes.Equal(raw, []byte{0xc1, 0xc0}) {
+		t.Fatalf("Raw() = %x, %v, want c1c0", raw, err)
+	}
+	if err := s.ListEnd(); err != nil {
+		t.Fatalf("ListEnd() failed: %v", err)
+	}
+	if _, _, err := s.Kind(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Kind() error = %v, want io.EOF", err)
+	}
+}
+
+func TestStreamConcatenatedItems(t *testing.T) {
+	data := []byte{0x83, 'f', 'o', 'o', 0x81, 0x80, 0x7f}
+	for _, limit := range []uint64{0, uint64(len(data))} {
+		s := NewStream(io.MultiReader(bytes.NewReader(data)), limit)
+		var got [][]byte
+		for {
+			b, err := s.Bytes()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Bytes() failed: %v", err)
+			}
+			got = append(got, b)
+		}
+		want := [][]byte{[]byte("foo"), {0x80}, {0x7f}}
+		if len(got) != len(want) {
+			t.Fatalf("got %d items, want %d", len(got), len(want))
+		}
+		for i := range want {
+			if !bytes.Equal(got[i], want[i]) {
+				t.Fatalf("item %d: got %x, want %x", i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		limit   uint64
+		read    func(s *Stream) error
+		wantErr error
+	}{
+		{
+			name:    "declared-size-exceeds-limit",
+			data:    []byte{0xbb, 0x7f, 0xff, 0xff, 0xff},
+			limit:   1024,
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "declared-list-size-exceeds-limit",
+			data:    []byte{0xfb, 0x7f, 0xff, 0xff, 0xff},
+			limit:   1024,
+			read:    func(s *Stream) error { _, err := s.List(); return err },
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "item-exceeds-list",
+			data:    []byte{0xc2, 0x83, 'd', 'o', 'g'},
+			read:    func(s *Stream) error { _, _ = s.List(); _, err := s.Bytes(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+		{
+			name:    "truncated-payload",
+			data:    []byte{0x83, 'd', 'o'},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "truncated-prefix",
+			data:    []byte{0xb9, 0x01},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+		{
+			name:    "non-canonical-single-byte",
+			data:    []byte{0x81, 0x01},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrNonCanonicalEncoding,
+		},
+		{
+			name:    "non-canonical-uint",
+			data:    []byte{0x82, 0x00, 0x01},
+			read:    func(s *Stream) error { _, err := s.Uint(); return err },
+			wantErr: ErrNonCanonicalEncoding,
+		},
+		{
+			name:    "uint-too-large",
+			data:    append([]byte{0x89}, bytes.Repeat([]byte{0xff}, 9)...),
+			read:    func(s *Stream) error { _, err := s.Uint(); return err },
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "bytes-from-list",
+			data:    []byte{0xc0},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:    "list-from-string",
+			data:    []byte{0x80},
+			read:    func(s *Stream) error { _, err := s.List(); return err },
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:    "list-end-with-remaining-items",
+			data:    []byte{0xc1, 0x80},
+			read:    func(s *Stream) error { _, _ = s.List(); return s.ListEnd() },
+			wantErr: ErrUnexpectedNumberOfItems,
+		},
+		{
+			name:    "list-end-outside-list",
+			data:    []byte{0x80},
+			read:    func(s *Stream) error { return s.ListEnd() },
+			wantErr: ErrNotInList,
+		},
+		{
+			name:    "empty-input",
+			data:    []byte{},
+			read:    func(s *Stream) error { _, _, err := s.Kind(); return err },
+			wantErr: io.EOF,
+		},
+		{
+			name:    "input-beyond-limit",
+			data:    []byte{0x80, 0x80},
+			limit:   1,
+			read:    func(s *Stream) error { _, _ = s.Bytes(); _, err := s.Bytes(); return err },
+			wantErr: io.EOF,
+		},
+		{
+			name:    "item-beyond-limit",
+			data:    []byte{0x83, 'd', 'o', 'g'},
+			limit:   3,
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.read(NewStream(bytes.NewReader(tt.data), tt.limit))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStreamLargeString(t *testing.T) {
+	enc, err := Encode(String(strings.Repeat("a", 1024)))
+	if err != nil {
+		t.Fatalf("Encode() failed: %v", err)
+	}
+	s := NewStream(strings.NewReader(string(enc)), 0)
+	raw, err := s.Raw()
+	if err != nil {
+		t.Fatalf("Raw() failed: %v", err)
+	}
+	if !bytes.Equal(raw, enc) {
+		t.Fatalf("Raw() = %x, want %x", raw, enc)
+	}
+}
+
+func expectKind(t *testing.T, s *Stream, kind Kind, size uint64) {
+	t.Helper()
+	k, n, err := s.Kind()
+	if err != nil {
+		t.Fatalf("Kind() failed: %v", err)
+	}
+	if k != kind || n != size {
+		t.Fatalf("Kind() = %v, %v, want %v, %v", k, n, kind, size)
+	}
+}
+
+func expectList(t *testing.T, s *Stream, size uint64) {
+	t.Helper()
+	n, err := s.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if n != size {
+		t.Fatalf("List() = %v, want %v", n, size)
+	}
+}
+
+func FuzzStream(f *testing.F) {
+	for _, s := range [][]byte{
+		{0xcc, 0xc7, 0x83, 'd', 'o', 'g', 0x82, 0x04, 0x00, 0x80, 0x05, 0xc1, 0xc0},
+		{0x83, 'f', 'o', 'o', 0x81, 0x80, 0x7f},
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Every item read from the stream must be accepted by DecodeLazy.
+		s := NewStream(bytes.NewReader(data), uint64(len(data)))
+		for {
+			raw, err := s.Raw()
+			if err != nil {
+				return
+			}
+			if _, n, err := DecodeLazy(raw); err != nil || n != len(raw) {
+				t.Fatalf("DecodeLazy(%x) = %v, %v", raw, n, err)
+			}
+		}
+	})
+}
+
+// plainReader hides the methods of the underlying reader, so NewStream
+// cannot determine the input size.
+type plainReader struct{ r io.Reader }
+
+func (p plainReader) Read(b []byte) (int, error) { return p.r.Read(b) }
+
+func TestStreamHugeDeclaredSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		read    func(s *Stream) error
+		wantErr error
+	}{
+		{
+			name:    "truncated-payload",
+			data:    []byte{0x83, 'd', 'o'},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+		{
+			name:    "huge-string-bytes",
+			data:    []byte{0xbe, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+		{
+			name:    "huge-string-raw",
+			data:    []byte{0xbe, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			read:    func(s *Stream) error { _, err := s.Raw(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+		{
+			name:    "huge-list-raw",
+			data:    []byte{0xfb, 0x7f, 0xff, 0xff, 0xff},
+			read:    func(s *Stream) error { _, err := s.Raw(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+		{
+			name:    "huge-string-4-bytes",
+			data:    []byte{0xbb, 0x7f, 0xff, 0xff, 0xff},
+			read:    func(s *Stream) error { _, err := s.Bytes(); return err },
+			wantErr: ErrUnexpectedEndOfData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/known-size", func(t *testing.T) {
+			err := tt.read(NewStream(bytes.NewReader(tt.data), 0))
+			if !errors.Is(err, ErrTooLarge) {
+				t.Fatalf("error = %v, want %v", err, ErrTooLarge)
+			}
+		})
+		t.Run(tt.name+"/unknown-size", func(t *testing.T) {
+			err := tt.read(NewStream(plainReader{bytes.NewReader(tt.data)}, 0))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStreamKnownSize(t *testing.T) {
+	data := []byte{0xbb, 0x7f, 0xff, 0xff, 0xff}
+	readers := map[string]io.Reader{
+		"bytes.Reader":   bytes.NewReader(data),
+		"strings.Reader": strings.NewReader(string(data)),
+		"bytes.Buffer":   bytes.NewBuffer(data),
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			s := NewStream(r, 0)
+			if _, _, err := s.Kind(); !errors.Is(err, ErrTooLarge) {
+				t.Fatalf("Kind() error = %v, want %v", err, ErrTooLarge)
+			}
+		})
+	}
+}
+
+func TestStreamDoesNotReadAhead(t *testing.T) {
+	r := bytes.NewReader([]byte{0x01, 0x82, 0x04, 0x00, 0x02, 0x03})
+	s := NewStream(plainReader{r}, 0)
+	raw, err := s.Raw()
+	if err != nil || !bytes.Equal(raw, []byte{0x01}) {
+		t.Fatalf("Raw() = %x, %v, want 01", raw, err)
+	}
+	if n := r.Len(); n != 5 {
+		t.Fatalf("unread bytes = %d, want 5", n)
+	}
+	u, err := s.Uint()
+	if err != nil || u != 1024 {
+		t.Fatalf("Uint() = %v, %v, want %v", u, err, 1024)
+	}
+	if n := r.Len(); n != 2 {
+		t.Fatalf("unread bytes = %d, want 2", n)
+	}
+}
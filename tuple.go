@@ -0,0 +1,497 @@
+package rlp
+
+import (
+	"reflect"
+)
+
+// Tuple2 is an RLP list of two items of different types. The types of the
+// items are checked at compile time, unlike in List.
+//
+// The item types, or pointers to them, must implement the Encoder interface
+// if the tuple is being encoded. The pointers to the item types must
+// implement the Decoder interface if the tuple is being decoded, unless the
+// item types are pointer types that implement it, like *BigInt, in which case
+// nil items are allocated during decoding.
+//
+// During decoding, the number of items in the data must match the number of
+// items in the tuple, otherwise ErrUnexpectedNumberOfItems is returned.
+//
+// Tuple3 to Tuple8 work the same way for more items.
+type Tuple2[A, B any] struct {
+	V1 A
+	V2 B
+}
+
+// Get returns the items of the tuple.
+func (t Tuple2[A, B]) Get() (A, B) {
+	return t.V1, t.V2
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple2[A, B]) Set(v1 A, v2 B) {
+	t.V1 = v1
+	t.V2 = v2
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple2[A, B]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple2[A, B]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple2[A, B]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple2[A, B]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple2[A, B]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple2[A, B]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple2[A, B]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple2[A, B]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2)}
+}
+
+// Tuple3 is an RLP list of three items of different types.
+//
+// See Tuple2 for details.
+type Tuple3[A, B, C any] struct {
+	V1 A
+	V2 B
+	V3 C
+}
+
+// Get returns the items of the tuple.
+func (t Tuple3[A, B, C]) Get() (A, B, C) {
+	return t.V1, t.V2, t.V3
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple3[A, B, C]) Set(v1 A, v2 B, v3 C) {
+	t.V1 = v1
+	t.V2 = v2
+	t.V3 = v3
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple3[A, B, C]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple3[A, B, C]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple3[A, B, C]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple3[A, B, C]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple3[A, B, C]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple3[A, B, C]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple3[A, B, C]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2), encoderOf(t.V3)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple3[A, B, C]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2), decoderOf(&t.V3)}
+}
+
+// Tuple4 is an RLP list of four items of different types.
+//
+// See Tuple2 for details.
+type Tuple4[A, B, C, D any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+}
+
+// Get returns the items of the tuple.
+func (t Tuple4[A, B, C, D]) Get() (A, B, C, D) {
+	return t.V1, t.V2, t.V3, t.V4
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple4[A, B, C, D]) Set(v1 A, v2 B, v3 C, v4 D) {
+	t.V1 = v1
+	t.V2 = v2
+	t.V3 = v3
+	t.V4 = v4
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple4[A, B, C, D]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple4[A, B, C, D]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple4[A, B, C, D]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple4[A, B, C, D]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple4[A, B, C, D]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple4[A, B, C, D]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple4[A, B, C, D]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2), encoderOf(t.V3), encoderOf(t.V4)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple4[A, B, C, D]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2), decoderOf(&t.V3), decoderOf(&t.V4)}
+}
+
+// Tuple5 is an RLP list of five items of different types.
+//
+// See Tuple2 for details.
+type Tuple5[A, B, C, D, E any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+}
+
+// Get returns the items of the tuple.
+func (t Tuple5[A, B, C, D, E]) Get() (A, B, C, D, E) {
+	return t.V1, t.V2, t.V3, t.V4, t.V5
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple5[A, B, C, D, E]) Set(v1 A, v2 B, v3 C, v4 D, v5 E) {
+	t.V1 = v1
+	t.V2 = v2
+	t.V3 = v3
+	t.V4 = v4
+	t.V5 = v5
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple5[A, B, C, D, E]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple5[A, B, C, D, E]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple5[A, B, C, D, E]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple5[A, B, C, D, E]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple5[A, B, C, D, E]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple5[A, B, C, D, E]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple5[A, B, C, D, E]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2), encoderOf(t.V3), encoderOf(t.V4), encoderOf(t.V5)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple5[A, B, C, D, E]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2), decoderOf(&t.V3), decoderOf(&t.V4), decoderOf(&t.V5)}
+}
+
+// Tuple6 is an RLP list of six items of different types.
+//
+// See Tuple2 for details.
+type Tuple6[A, B, C, D, E, F any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+	V6 F
+}
+
+// Get returns the items of the tuple.
+func (t Tuple6[A, B, C, D, E, F]) Get() (A, B, C, D, E, F) {
+	return t.V1, t.V2, t.V3, t.V4, t.V5, t.V6
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple6[A, B, C, D, E, F]) Set(v1 A, v2 B, v3 C, v4 D, v5 E, v6 F) {
+	t.V1 = v1
+	t.V2 = v2
+	t.V3 = v3
+	t.V4 = v4
+	t.V5 = v5
+	t.V6 = v6
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple6[A, B, C, D, E, F]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple6[A, B, C, D, E, F]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple6[A, B, C, D, E, F]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple6[A, B, C, D, E, F]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple6[A, B, C, D, E, F]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple6[A, B, C, D, E, F]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple6[A, B, C, D, E, F]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2), encoderOf(t.V3), encoderOf(t.V4), encoderOf(t.V5), encoderOf(t.V6)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple6[A, B, C, D, E, F]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2), decoderOf(&t.V3), decoderOf(&t.V4), decoderOf(&t.V5), decoderOf(&t.V6)}
+}
+
+// Tuple7 is an RLP list of seven items of different types.
+//
+// See Tuple2 for details.
+type Tuple7[A, B, C, D, E, F, G any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+	V6 F
+	V7 G
+}
+
+// Get returns the items of the tuple.
+func (t Tuple7[A, B, C, D, E, F, G]) Get() (A, B, C, D, E, F, G) {
+	return t.V1, t.V2, t.V3, t.V4, t.V5, t.V6, t.V7
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple7[A, B, C, D, E, F, G]) Set(v1 A, v2 B, v3 C, v4 D, v5 E, v6 F, v7 G) {
+	t.V1 = v1
+	t.V2 = v2
+	t.V3 = v3
+	t.V4 = v4
+	t.V5 = v5
+	t.V6 = v6
+	t.V7 = v7
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple7[A, B, C, D, E, F, G]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple7[A, B, C, D, E, F, G]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple7[A, B, C, D, E, F, G]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple7[A, B, C, D, E, F, G]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple7[A, B, C, D, E, F, G]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple7[A, B, C, D, E, F, G]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple7[A, B, C, D, E, F, G]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2), encoderOf(t.V3), encoderOf(t.V4), encoderOf(t.V5), encoderOf(t.V6), encoderOf(t.V7)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple7[A, B, C, D, E, F, G]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2), decoderOf(&t.V3), decoderOf(&t.V4), decoderOf(&t.V5), decoderOf(&t.V6), decoderOf(&t.V7)}
+}
+
+// Tuple8 is an RLP list of eight items of different types.
+//
+// See Tuple2 for details.
+type Tuple8[A, B, C, D, E, F, G, H any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+	V6 F
+	V7 G
+	V8 H
+}
+
+// Get returns the items of the tuple.
+func (t Tuple8[A, B, C, D, E, F, G, H]) Get() (A, B, C, D, E, F, G, H) {
+	return t.V1, t.V2, t.V3, t.V4, t.V5, t.V6, t.V7, t.V8
+}
+
+// Set sets the items of the tuple.
+func (t *Tuple8[A, B, C, D, E, F, G, H]) Set(v1 A, v2 B, v3 C, v4 D, v5 E, v6 F, v7 G, v8 H) {
+	t.V1 = v1
+	t.V2 = v2
+	t.V3 = v3
+	t.V4 = v4
+	t.V5 = v5
+	t.V6 = v6
+	t.V7 = v7
+	t.V8 = v8
+}
+
+// EncodeRLP implements the Encoder interface.
+func (t Tuple8[A, B, C, D, E, F, G, H]) EncodeRLP() ([]byte, error) {
+	return appendTypedList(nil, t.items())
+}
+
+// AppendRLP implements the Appender interface.
+func (t Tuple8[A, B, C, D, E, F, G, H]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, t.items())
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the tuple cannot be encoded, it returns 0.
+func (t Tuple8[A, B, C, D, E, F, G, H]) EncodedSize() int {
+	n, _ := encodedSize(t)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (t Tuple8[A, B, C, D, E, F, G, H]) eachItem(fn func(item any) error) error {
+	return forEachItem(t.items(), fn)
+}
+
+// DecodeRLP implements the Decoder interface.
+func (t *Tuple8[A, B, C, D, E, F, G, H]) DecodeRLP(data []byte) (int, error) {
+	return t.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (t *Tuple8[A, B, C, D, E, F, G, H]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTuple(data, t.decoders(), reflect.TypeOf(t).Elem(), s)
+}
+
+// items returns the items of the tuple for encoding.
+func (t Tuple8[A, B, C, D, E, F, G, H]) items() []any {
+	return []any{encoderOf(t.V1), encoderOf(t.V2), encoderOf(t.V3), encoderOf(t.V4), encoderOf(t.V5), encoderOf(t.V6), encoderOf(t.V7), encoderOf(t.V8)}
+}
+
+// decoders returns the items of the tuple for decoding, see decoderOf.
+func (t *Tuple8[A, B, C, D, E, F, G, H]) decoders() []any {
+	return []any{decoderOf(&t.V1), decoderOf(&t.V2), decoderOf(&t.V3), decoderOf(&t.V4), decoderOf(&t.V5), decoderOf(&t.V6), decoderOf(&t.V7), decoderOf(&t.V8)}
+}
+
+// decodeTuple decodes RLP list item into the given tuple items, which are
+// returned by the decoders method of the tuple of the given type.
+func decodeTuple(src []byte, items []any, typ reflect.Type, s *decodeState) (int, error) {
+	return decodeTypedListAs(src, &items, func() any { return nil }, false, typ, s)
+}
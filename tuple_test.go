@@ -0,0 +1,124 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestTupleEncode(t *testing.T) {
+	tests := []struct {
+		data Encoder
+		want []byte
+	}{
+		{Tuple2[String, Uint]{V1: "dog", V2: 1}, []byte{0xc5, 0x83, 'd', 'o', 'g', 0x01}},
+		{Tuple3[Uint, Bool, Bytes]{V1: 0, V2: true, V3: Bytes{0xff}}, []byte{0xc4, 0x80, 0x01, 0x81, 0xff}},
+		{Tuple2[*BigInt, Uint]{V1: (*BigInt)(big.NewInt(5)), V2: 3}, []byte{0xc2, 0x05, 0x03}},
+		{Tuple2[Uint, TypedList[Uint]]{V1: 1, V2: TypedList[Uint]{ptr(Uint(2))}}, []byte{0xc3, 0x01, 0xc1, 0x02}},
+		{
+			Tuple8[Uint, Uint, Uint, Uint, Uint, Uint, Uint, Uint]{V1: 1, V2: 2, V3: 3, V4: 4, V5: 5, V6: 6, V7: 7, V8: 8},
+			[]byte{0xc8, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := tt.data.(Sizer).EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestTupleDecode(t *testing.T) {
+	var tuple Tuple3[String, Uint, VarTypedList[Uint]]
+	if _, err := Decode([]byte{0xc7, 0x83, 'd', 'o', 'g', 0x01, 0xc1, 0x02}, &tuple); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s, u, l := tuple.Get()
+	if s != "dog" || u != 1 || len(l) != 1 || *l[0] != 2 {
+		t.Fatalf("unexpected result %v, %v, %v", s, u, l)
+	}
+
+	tupleType := reflect.TypeOf(Tuple2[String, Uint]{})
+	tests := []struct {
+		data     []byte
+		wantErr  error
+		wantPath string
+		wantType reflect.Type
+	}{
+		{[]byte{0xc1, 0x01}, ErrUnexpectedNumberOfItems, "", tupleType},
+		{[]byte{0xc3, 0x80, 0x01, 0x02}, ErrUnexpectedNumberOfItems, "[2]", nil},
+		{[]byte{0xc2, 0xc0, 0x01}, ErrUnsupportedType, "[0]", reflect.TypeOf("")},
+		{[]byte{0x80}, ErrUnsupportedType, "", tupleType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var tuple Tuple2[String, Uint]
+			_, err := Decode(tt.data, &tuple)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			var decErr *DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("expected DecodeError, got %T", err)
+			}
+			if decErr.PathString() != tt.wantPath {
+				t.Fatalf("expected path %q, got %q", tt.wantPath, decErr.PathString())
+			}
+			if decErr.Type != tt.wantType {
+				t.Fatalf("expected type %v, got %v", tt.wantType, decErr.Type)
+			}
+		})
+	}
+}
+
+func TestTupleDecodePointer(t *testing.T) {
+	var tuple Tuple2[*BigInt, Uint]
+	if _, err := Decode([]byte{0xc2, 0x05, 0x03}, &tuple); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tuple.V1 == nil || tuple.V1.Get().Int64() != 5 || tuple.V2 != 3 {
+		t.Fatalf("unexpected result %v, %v", tuple.V1, tuple.V2)
+	}
+
+	// Existing values are decoded into.
+	v1 := new(BigInt)
+	tuple = Tuple2[*BigInt, Uint]{V1: v1}
+	if _, err := Decode([]byte{0xc2, 0x07, 0x03}, &tuple); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tuple.V1 != v1 || v1.Get().Int64() != 7 {
+		t.Fatalf("unexpected result %v", tuple.V1)
+	}
+
+	var nilItem Tuple2[*BigInt, Uint]
+	if _, err := Encode(nilItem); !errors.Is(err, ErrNilValue) {
+		t.Fatalf("expected error %v, got %v", ErrNilValue, err)
+	}
+}
+
+func TestTupleSet(t *testing.T) {
+	var tuple Tuple2[String, Uint]
+	tuple.Set("cat", 7)
+	data, err := Encode(tuple)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got Tuple2[String, Uint]
+	if _, err := Decode(data, &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got != tuple {
+		t.Fatalf("expected %v, got %v", tuple, got)
+	}
+}
@@ -18,6 +18,21 @@ func (r RLP) Decode(dst Decoder) error {
 	return err
 }
 
+// DecodeWithOptions works like Decode, but it verifies that the data does not
+// exceed the limits set in the options before decoding it.
+//
+// Any data after the first item is ignored.
+func (r RLP) DecodeWithOptions(dst Decoder, opts DecodeOptions) error {
+	if isNil(dst) {
+		return ErrNilValue
+	}
+	if _, err := walk(r, &opts); err != nil {
+		return err
+	}
+	_, err := decodeItem(dst, r, &decodeState{opts: &opts, input: r})
+	return err
+}
+
 // Length returns the length of the string or number of items in the list.
 // If the item is invalid, it returns 0.
 func (r RLP) Length() int {
@@ -33,17 +48,12 @@ func (r RLP) Length() int {
 		return int(dataLen)
 	}
 	n := 0
-	d := r[prefixLen:totalLen]
-	for ; len(d) > 0; n++ {
-		_, dataLen, prefixLen, err := decodePrefix(d)
-		if err != nil {
-			return 0
-		}
-		itemLen := int(dataLen + uint64(prefixLen))
-		if itemLen > len(d) || itemLen == 0 {
-			return 0
-		}
-		d = d[itemLen:]
+	it := r.Iter()
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil {
+		return 0
 	}
 	return n
 }
@@ -67,6 +77,8 @@ func (r RLP) Bytes() (v Bytes, err error) {
 //
 // The list may be of any length. Items of the returned list are raw RLP
 // items that can be decoded further.
+//
+// To iterate over the items without allocating the list, use Iter.
 func (r RLP) List() (l VarTypedList[RLP], err error) {
 	_, err = (&l).DecodeRLP(r)
 	return
@@ -79,6 +91,13 @@ func (r RLP) Uint() (v Uint, err error) {
 	return
 }
 
+// Bool attempts to decode itself as a boolean. If the decoding is
+// successful, it returns the decoded value.
+func (r RLP) Bool() (v Bool, err error) {
+	_, err = (&v).DecodeRLP(r)
+	return
+}
+
 // BigInt attempts to decode itself as a big.Int. If the decoding is
 // successful, it returns the decoded big.Int.
 func (r RLP) BigInt() (v *BigInt, err error) {
@@ -87,6 +106,13 @@ func (r RLP) BigInt() (v *BigInt, err error) {
 	return
 }
 
+// Uint256 attempts to decode itself as a 256-bit unsigned integer. If the
+// decoding is successful, it returns the decoded value.
+func (r RLP) Uint256() (v Uint256, err error) {
+	_, err = (&v).DecodeRLP(r)
+	return
+}
+
 // IsString returns true if the encoded data is an RLP string.
 // If the RLP data is empty, it returns false.
 func (r RLP) IsString() bool {
@@ -118,17 +144,46 @@ func (r RLP) EncodeRLP() ([]byte, error) {
 	return r[:totalLen], nil
 }
 
+// AppendRLP implements the Appender interface.
+func (r RLP) AppendRLP(dst []byte) ([]byte, error) {
+	data, err := r.EncodeRLP()
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, data...), nil
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the data is not a valid RLP item, it returns 0.
+func (r RLP) EncodedSize() int {
+	_, dataLen, prefixLen, err := decodePrefix(r)
+	if err != nil {
+		return 0
+	}
+	totalLen := int(dataLen + uint64(prefixLen))
+	if len(r) < totalLen {
+		return 0
+	}
+	return totalLen
+}
+
 // DecodeRLP implements the Decoder interface.
 //
 // The decoded value shares memory with the given data.
 func (r *RLP) DecodeRLP(data []byte) (int, error) {
-	_, dataLen, prefixLen, err := decodePrefix(data)
+	return r.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (r *RLP) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	_, dataLen, prefixLen, err := s.decodePrefix(data)
 	if err != nil {
-		return 0, err
+		return 0, decodeError(err, rlpType)
 	}
 	totalLen := int(dataLen + uint64(prefixLen))
 	if totalLen > len(data) {
-		return 0, ErrUnexpectedEndOfData
+		return 0, decodeError(ErrUnexpectedEndOfData, rlpType)
 	}
 	*r = data[:totalLen]
 	return totalLen, nil
@@ -149,12 +204,27 @@ func (s *String) Set(value string) {
 
 // EncodeRLP implements the Encoder interface.
 func (s String) EncodeRLP() ([]byte, error) {
-	return encodeString(string(s))
+	return appendString(nil, string(s))
+}
+
+// AppendRLP implements the Appender interface.
+func (s String) AppendRLP(dst []byte) ([]byte, error) {
+	return appendString(dst, string(s))
+}
+
+// EncodedSize implements the Sizer interface.
+func (s String) EncodedSize() int {
+	return stringSize(string(s))
 }
 
 // DecodeRLP implements the Decoder interface.
 func (s *String) DecodeRLP(data []byte) (int, error) {
-	return decodeString(data, (*string)(s))
+	return s.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (s *String) decodeRLPState(data []byte, st *decodeState) (int, error) {
+	return decodeString(data, (*string)(s), st)
 }
 
 // Bytes is a byte slice type that can be encoded and decoded to/from RLP.
@@ -177,14 +247,167 @@ func (b *Bytes) Ptr() *[]byte {
 
 // EncodeRLP implements the Encoder interface.
 func (b Bytes) EncodeRLP() ([]byte, error) {
-	return encodeBytes(b)
+	return appendBytes(nil, b)
+}
+
+// AppendRLP implements the Appender interface.
+func (b Bytes) AppendRLP(dst []byte) ([]byte, error) {
+	return appendBytes(dst, b)
+}
+
+// EncodedSize implements the Sizer interface.
+func (b Bytes) EncodedSize() int {
+	return bytesSize(b)
 }
 
 // DecodeRLP implements the Decoder interface.
 //
 // The decoded value shares memory with the given data.
 func (b *Bytes) DecodeRLP(data []byte) (int, error) {
-	return decodeBytes(data, (*[]byte)(b))
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *Bytes) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeBytes(data, (*[]byte)(b), s)
+}
+
+// Bytes20 is a 20-byte array type that can be encoded and decoded to/from RLP.
+//
+// The decoded string must be exactly 20 bytes long, otherwise ErrInvalidLength
+// is returned.
+type Bytes20 [20]byte
+
+// Get returns the byte array.
+func (b Bytes20) Get() [20]byte {
+	return b
+}
+
+// Ptr returns a pointer to the byte array.
+func (b *Bytes20) Ptr() *[20]byte {
+	return (*[20]byte)(b)
+}
+
+// Set sets the byte array.
+func (b *Bytes20) Set(value [20]byte) {
+	*b = value
+}
+
+// EncodeRLP implements the Encoder interface.
+func (b Bytes20) EncodeRLP() ([]byte, error) {
+	return appendBytes(nil, b[:])
+}
+
+// AppendRLP implements the Appender interface.
+func (b Bytes20) AppendRLP(dst []byte) ([]byte, error) {
+	return appendBytes(dst, b[:])
+}
+
+// EncodedSize implements the Sizer interface.
+func (b Bytes20) EncodedSize() int {
+	return bytesSize(b[:])
+}
+
+// DecodeRLP implements the Decoder interface.
+func (b *Bytes20) DecodeRLP(data []byte) (int, error) {
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *Bytes20) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeFixedBytes(data, b[:], bytes20Type, s)
+}
+
+// Bytes32 is a 32-byte array type that can be encoded and decoded to/from RLP.
+//
+// The decoded string must be exactly 32 bytes long, otherwise ErrInvalidLength
+// is returned.
+type Bytes32 [32]byte
+
+// Get returns the byte array.
+func (b Bytes32) Get() [32]byte {
+	return b
+}
+
+// Ptr returns a pointer to the byte array.
+func (b *Bytes32) Ptr() *[32]byte {
+	return (*[32]byte)(b)
+}
+
+// Set sets the byte array.
+func (b *Bytes32) Set(value [32]byte) {
+	*b = value
+}
+
+// EncodeRLP implements the Encoder interface.
+func (b Bytes32) EncodeRLP() ([]byte, error) {
+	return appendBytes(nil, b[:])
+}
+
+// AppendRLP implements the Appender interface.
+func (b Bytes32) AppendRLP(dst []byte) ([]byte, error) {
+	return appendBytes(dst, b[:])
+}
+
+// EncodedSize implements the Sizer interface.
+func (b Bytes32) EncodedSize() int {
+	return bytesSize(b[:])
+}
+
+// DecodeRLP implements the Decoder interface.
+func (b *Bytes32) DecodeRLP(data []byte) (int, error) {
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *Bytes32) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeFixedBytes(data, b[:], bytes32Type, s)
+}
+
+// Bytes256 is a 256-byte array type that can be encoded and decoded to/from RLP.
+//
+// The decoded string must be exactly 256 bytes long, otherwise ErrInvalidLength
+// is returned.
+type Bytes256 [256]byte
+
+// Get returns the byte array.
+func (b Bytes256) Get() [256]byte {
+	return b
+}
+
+// Ptr returns a pointer to the byte array.
+func (b *Bytes256) Ptr() *[256]byte {
+	return (*[256]byte)(b)
+}
+
+// Set sets the byte array.
+func (b *Bytes256) Set(value [256]byte) {
+	*b = value
+}
+
+// EncodeRLP implements the Encoder interface.
+func (b Bytes256) EncodeRLP() ([]byte, error) {
+	return appendBytes(nil, b[:])
+}
+
+// AppendRLP implements the Appender interface.
+func (b Bytes256) AppendRLP(dst []byte) ([]byte, error) {
+	return appendBytes(dst, b[:])
+}
+
+// EncodedSize implements the Sizer interface.
+func (b Bytes256) EncodedSize() int {
+	return bytesSize(b[:])
+}
+
+// DecodeRLP implements the Decoder interface.
+func (b *Bytes256) DecodeRLP(data []byte) (int, error) {
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *Bytes256) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeFixedBytes(data, b[:], bytes256Type, s)
 }
 
 // Uint is an uint64 type that can be encoded and decoded to/from RLP.
@@ -207,15 +430,343 @@ func (u *Uint) Set(value uint64) {
 
 // EncodeRLP implements the Encoder interface.
 func (u Uint) EncodeRLP() ([]byte, error) {
-	return encodeUint(uint64(u))
+	return appendUint(nil, uint64(u))
+}
+
+// AppendRLP implements the Appender interface.
+func (u Uint) AppendRLP(dst []byte) ([]byte, error) {
+	return appendUint(dst, uint64(u))
+}
+
+// EncodedSize implements the Sizer interface.
+func (u Uint) EncodedSize() int {
+	return uintSize(uint64(u))
 }
 
 // DecodeRLP implements the Decoder interface.
 func (u *Uint) DecodeRLP(data []byte) (int, error) {
-	return decodeUint(data, (*uint64)(u))
+	return u.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (u *Uint) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeUint(data, (*uint64)(u), s)
+}
+
+// Uint8 is an uint8 type that can be encoded and decoded to/from RLP.
+//
+// If the decoded value does not fit into uint8, ErrTooLarge is returned.
+type Uint8 uint8
+
+// Get returns the uint8 value.
+func (u Uint8) Get() uint8 {
+	return uint8(u)
+}
+
+// Ptr returns a pointer to the uint8 value.
+func (u *Uint8) Ptr() *uint8 {
+	return (*uint8)(u)
+}
+
+// Set sets the uint8 value.
+func (u *Uint8) Set(value uint8) {
+	*u = Uint8(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (u Uint8) EncodeRLP() ([]byte, error) {
+	return appendUint(nil, uint64(u))
+}
+
+// AppendRLP implements the Appender interface.
+func (u Uint8) AppendRLP(dst []byte) ([]byte, error) {
+	return appendUint(dst, uint64(u))
+}
+
+// EncodedSize implements the Sizer interface.
+func (u Uint8) EncodedSize() int {
+	return uintSize(uint64(u))
+}
+
+// DecodeRLP implements the Decoder interface.
+func (u *Uint8) DecodeRLP(data []byte) (int, error) {
+	return u.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (u *Uint8) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	v, n, err := readUint(data, 1, uint8Type, s)
+	if err != nil {
+		return 0, err
+	}
+	*u = Uint8(v)
+	return n, nil
+}
+
+// Uint16 is an uint16 type that can be encoded and decoded to/from RLP.
+//
+// If the decoded value does not fit into uint16, ErrTooLarge is returned.
+type Uint16 uint16
+
+// Get returns the uint16 value.
+func (u Uint16) Get() uint16 {
+	return uint16(u)
+}
+
+// Ptr returns a pointer to the uint16 value.
+func (u *Uint16) Ptr() *uint16 {
+	return (*uint16)(u)
+}
+
+// Set sets the uint16 value.
+func (u *Uint16) Set(value uint16) {
+	*u = Uint16(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (u Uint16) EncodeRLP() ([]byte, error) {
+	return appendUint(nil, uint64(u))
+}
+
+// AppendRLP implements the Appender interface.
+func (u Uint16) AppendRLP(dst []byte) ([]byte, error) {
+	return appendUint(dst, uint64(u))
+}
+
+// EncodedSize implements the Sizer interface.
+func (u Uint16) EncodedSize() int {
+	return uintSize(uint64(u))
+}
+
+// DecodeRLP implements the Decoder interface.
+func (u *Uint16) DecodeRLP(data []byte) (int, error) {
+	return u.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (u *Uint16) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	v, n, err := readUint(data, 2, uint16Type, s)
+	if err != nil {
+		return 0, err
+	}
+	*u = Uint16(v)
+	return n, nil
+}
+
+// Uint32 is an uint32 type that can be encoded and decoded to/from RLP.
+//
+// If the decoded value does not fit into uint32, ErrTooLarge is returned.
+type Uint32 uint32
+
+// Get returns the uint32 value.
+func (u Uint32) Get() uint32 {
+	return uint32(u)
+}
+
+// Ptr returns a pointer to the uint32 value.
+func (u *Uint32) Ptr() *uint32 {
+	return (*uint32)(u)
+}
+
+// Set sets the uint32 value.
+func (u *Uint32) Set(value uint32) {
+	*u = Uint32(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (u Uint32) EncodeRLP() ([]byte, error) {
+	return appendUint(nil, uint64(u))
+}
+
+// AppendRLP implements the Appender interface.
+func (u Uint32) AppendRLP(dst []byte) ([]byte, error) {
+	return appendUint(dst, uint64(u))
+}
+
+// EncodedSize implements the Sizer interface.
+func (u Uint32) EncodedSize() int {
+	return uintSize(uint64(u))
+}
+
+// DecodeRLP implements the Decoder interface.
+func (u *Uint32) DecodeRLP(data []byte) (int, error) {
+	return u.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (u *Uint32) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	v, n, err := readUint(data, 4, uint32Type, s)
+	if err != nil {
+		return 0, err
+	}
+	*u = Uint32(v)
+	return n, nil
+}
+
+// Int is an int64 type that can be encoded and decoded to/from RLP.
+//
+// RLP does not define signed integers, so the value is encoded as an RLP
+// integer using the zigzag encoding: non-negative values x are encoded as
+// 2*x, and negative values as -2*x-1. For example, 0, -1, 1, -2 are encoded
+// as 0, 1, 2, 3. This is the same encoding as used by SignedBigInt.
+//
+// For the two's complement encoding, use TwosComplementInt.
+type Int int64
+
+// Get returns the int64 value.
+func (i Int) Get() int64 {
+	return int64(i)
+}
+
+// Ptr returns a pointer to the int64 value.
+func (i *Int) Ptr() *int64 {
+	return (*int64)(i)
+}
+
+// Set sets the int64 value.
+func (i *Int) Set(value int64) {
+	*i = Int(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (i Int) EncodeRLP() ([]byte, error) {
+	return appendUint(nil, zigzag(int64(i)))
+}
+
+// AppendRLP implements the Appender interface.
+func (i Int) AppendRLP(dst []byte) ([]byte, error) {
+	return appendUint(dst, zigzag(int64(i)))
+}
+
+// EncodedSize implements the Sizer interface.
+func (i Int) EncodedSize() int {
+	return uintSize(zigzag(int64(i)))
+}
+
+// DecodeRLP implements the Decoder interface.
+//
+// If the value does not fit into int64, ErrTooLarge is returned.
+func (i *Int) DecodeRLP(data []byte) (int, error) {
+	return i.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (i *Int) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	u, n, err := readUint(data, 8, int64Type, s)
+	if err != nil {
+		return 0, err
+	}
+	*i = Int(unzigzag(u))
+	return n, nil
+}
+
+// TwosComplementInt is an int64 type that can be encoded and decoded to/from
+// RLP.
+//
+// The value is encoded as an RLP string holding the minimal big endian two's
+// complement representation of the value, that is, without bytes that only
+// extend the sign of the next byte. For example, 1, -1, 128 and -128 are
+// encoded as 0x01, 0xff, 0x0080 and 0x80. Zero is encoded as an empty string.
+//
+// Non-negative values below 0x80 are encoded the same way as the Uint type.
+type TwosComplementInt int64
+
+// Get returns the int64 value.
+func (i TwosComplementInt) Get() int64 {
+	return int64(i)
+}
+
+// Ptr returns a pointer to the int64 value.
+func (i *TwosComplementInt) Ptr() *int64 {
+	return (*int64)(i)
+}
+
+// Set sets the int64 value.
+func (i *TwosComplementInt) Set(value int64) {
+	*i = TwosComplementInt(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (i TwosComplementInt) EncodeRLP() ([]byte, error) {
+	return appendTwosComplementInt(nil, int64(i))
+}
+
+// AppendRLP implements the Appender interface.
+func (i TwosComplementInt) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTwosComplementInt(dst, int64(i))
+}
+
+// EncodedSize implements the Sizer interface.
+func (i TwosComplementInt) EncodedSize() int {
+	var b [8]byte
+	return bytesSize(b[:twosComplement(&b, int64(i))])
+}
+
+// DecodeRLP implements the Decoder interface.
+//
+// If the value does not fit into int64, ErrTooLarge is returned.
+func (i *TwosComplementInt) DecodeRLP(data []byte) (int, error) {
+	return i.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (i *TwosComplementInt) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTwosComplementInt(data, (*int64)(i), s)
+}
+
+// Bool is a bool type that can be encoded and decoded to/from RLP.
+//
+// False is encoded as an empty string (0x80) and true as a single byte
+// 0x01. Any other encoding is rejected with ErrInvalidBool, except in the
+// lenient mode enabled by DecodeOptions.AllowNonCanonical, in which other
+// encodings of the integers 0 and 1 are accepted and reported as
+// non-canonical.
+type Bool bool
+
+// Get returns the bool value.
+func (b Bool) Get() bool {
+	return bool(b)
+}
+
+// Ptr returns a pointer to the bool value.
+func (b *Bool) Ptr() *bool {
+	return (*bool)(b)
+}
+
+// Set sets the bool value.
+func (b *Bool) Set(value bool) {
+	*b = Bool(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (b Bool) EncodeRLP() ([]byte, error) {
+	return appendBool(nil, bool(b))
+}
+
+// AppendRLP implements the Appender interface.
+func (b Bool) AppendRLP(dst []byte) ([]byte, error) {
+	return appendBool(dst, bool(b))
+}
+
+// EncodedSize implements the Sizer interface.
+func (b Bool) EncodedSize() int {
+	return 1
+}
+
+// DecodeRLP implements the Decoder interface.
+func (b *Bool) DecodeRLP(data []byte) (int, error) {
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *Bool) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeBool(data, (*bool)(b), s)
 }
 
 // BigInt is a big.Int type that can be encoded and decoded to/from RLP.
+//
+// RLP integers are unsigned, so negative values cannot be encoded and
+// ErrNegativeValue is returned instead. Use SignedBigInt for signed values.
 type BigInt big.Int
 
 // Get returns the big.Int value.
@@ -235,12 +786,82 @@ func (b *BigInt) Set(value *big.Int) {
 
 // EncodeRLP implements the Encoder interface.
 func (b BigInt) EncodeRLP() ([]byte, error) {
-	return encodeBigInt((*big.Int)(&b))
+	return appendBigInt(nil, (*big.Int)(&b))
+}
+
+// AppendRLP implements the Appender interface.
+func (b BigInt) AppendRLP(dst []byte) ([]byte, error) {
+	return appendBigInt(dst, (*big.Int)(&b))
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the value is negative, it returns 0.
+func (b BigInt) EncodedSize() int {
+	if (*big.Int)(&b).Sign() < 0 {
+		return 0
+	}
+	return bigIntSize((*big.Int)(&b))
 }
 
 // DecodeRLP implements the Decoder interface.
 func (b *BigInt) DecodeRLP(data []byte) (int, error) {
-	return decodeBigInt(data, (*big.Int)(b))
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *BigInt) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeBigInt(data, (*big.Int)(b), s)
+}
+
+// SignedBigInt is a signed big.Int type that can be encoded and decoded
+// to/from RLP.
+//
+// RLP does not define signed integers, so the value is encoded as an RLP
+// integer using the zigzag encoding: non-negative values x are encoded as
+// 2*x, and negative values as -2*x-1. For example, 0, -1, 1, -2 are encoded
+// as 0, 1, 2, 3. Both sides must agree on this convention, because the
+// encoding of non-negative values differs from the BigInt type.
+type SignedBigInt big.Int
+
+// Get returns the big.Int value.
+func (b *SignedBigInt) Get() *big.Int {
+	return (*big.Int)(b)
+}
+
+// Ptr returns a pointer to the big.Int value.
+func (b *SignedBigInt) Ptr() *big.Int {
+	return (*big.Int)(b)
+}
+
+// Set sets the big.Int value.
+func (b *SignedBigInt) Set(value *big.Int) {
+	(*big.Int)(b).Set(value)
+}
+
+// EncodeRLP implements the Encoder interface.
+func (b SignedBigInt) EncodeRLP() ([]byte, error) {
+	return appendSignedBigInt(nil, (*big.Int)(&b))
+}
+
+// AppendRLP implements the Appender interface.
+func (b SignedBigInt) AppendRLP(dst []byte) ([]byte, error) {
+	return appendSignedBigInt(dst, (*big.Int)(&b))
+}
+
+// EncodedSize implements the Sizer interface.
+func (b SignedBigInt) EncodedSize() int {
+	return signedBigIntSize((*big.Int)(&b))
+}
+
+// DecodeRLP implements the Decoder interface.
+func (b *SignedBigInt) DecodeRLP(data []byte) (int, error) {
+	return b.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (b *SignedBigInt) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeSignedBigInt(data, (*big.Int)(b), s)
 }
 
 // List represents a list of RLP items.
@@ -280,12 +901,35 @@ func (l *List) Add(items ...any) {
 
 // EncodeRLP implements the Encoder interface.
 func (l List) EncodeRLP() ([]byte, error) {
-	return encodeList(l)
+	return appendList(nil, l)
+}
+
+// AppendRLP implements the Appender interface.
+func (l List) AppendRLP(dst []byte) ([]byte, error) {
+	return appendList(dst, l)
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the list cannot be encoded, it returns 0.
+func (l List) EncodedSize() int {
+	n, _ := encodedSize(l)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (l List) eachItem(fn func(item any) error) error {
+	return forEachItem(l, fn)
 }
 
 // DecodeRLP implements the Decoder interface.
 func (l *List) DecodeRLP(data []byte) (int, error) {
-	return decodeList(data, (*[]any)(l))
+	return l.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (l *List) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeList(data, (*[]any)(l), s)
 }
 
 // TypedList represents a RLP list of a specific type.
@@ -325,12 +969,35 @@ func (l *TypedList[T]) Add(items ...*T) {
 
 // EncodeRLP implements the Encoder interface.
 func (l TypedList[T]) EncodeRLP() ([]byte, error) {
-	return encodeTypedList(l)
+	return appendTypedList(nil, l)
+}
+
+// AppendRLP implements the Appender interface.
+func (l TypedList[T]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, l)
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the list cannot be encoded, it returns 0.
+func (l TypedList[T]) EncodedSize() int {
+	n, _ := encodedSize(l)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (l TypedList[T]) eachItem(fn func(item any) error) error {
+	return forEachItem(l, fn)
 }
 
 // DecodeRLP implements the Decoder interface.
 func (l *TypedList[T]) DecodeRLP(data []byte) (int, error) {
-	return decodeTypedList(data, (*[]*T)(l), func() *T { return new(T) }, false)
+	return l.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (l *TypedList[T]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTypedList(data, (*[]*T)(l), func() *T { return new(T) }, false, s)
 }
 
 // VarList represents an RLP list of variable length whose items are decoded
@@ -365,12 +1032,35 @@ func (l *VarList) Add(items ...any) {
 
 // EncodeRLP implements the Encoder interface.
 func (l VarList) EncodeRLP() ([]byte, error) {
-	return encodeList(l)
+	return appendList(nil, l)
+}
+
+// AppendRLP implements the Appender interface.
+func (l VarList) AppendRLP(dst []byte) ([]byte, error) {
+	return appendList(dst, l)
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the list cannot be encoded, it returns 0.
+func (l VarList) EncodedSize() int {
+	n, _ := encodedSize(l)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (l VarList) eachItem(fn func(item any) error) error {
+	return forEachItem(l, fn)
 }
 
 // DecodeRLP implements the Decoder interface.
 func (l *VarList) DecodeRLP(data []byte) (int, error) {
-	return decodeTypedList(data, (*[]any)(l), func() any { return new(RLP) }, true)
+	return l.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (l *VarList) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTypedList(data, (*[]any)(l), func() any { return new(RLP) }, true, s)
 }
 
 // VarTypedList represents an RLP list of variable length whose items are all
@@ -406,10 +1096,33 @@ func (l *VarTypedList[T]) Add(items ...*T) {
 
 // EncodeRLP implements the Encoder interface.
 func (l VarTypedList[T]) EncodeRLP() ([]byte, error) {
-	return encodeTypedList(l)
+	return appendTypedList(nil, l)
+}
+
+// AppendRLP implements the Appender interface.
+func (l VarTypedList[T]) AppendRLP(dst []byte) ([]byte, error) {
+	return appendTypedList(dst, l)
+}
+
+// EncodedSize implements the Sizer interface.
+//
+// If the list cannot be encoded, it returns 0.
+func (l VarTypedList[T]) EncodedSize() int {
+	n, _ := encodedSize(l)
+	return n
+}
+
+// eachItem implements the itemLister interface.
+func (l VarTypedList[T]) eachItem(fn func(item any) error) error {
+	return forEachItem(l, fn)
 }
 
 // DecodeRLP implements the Decoder interface.
 func (l *VarTypedList[T]) DecodeRLP(data []byte) (int, error) {
-	return decodeTypedList(data, (*[]*T)(l), func() *T { return new(T) }, true)
+	return l.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (l *VarTypedList[T]) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	return decodeTypedList(data, (*[]*T)(l), func() *T { return new(T) }, true, s)
 }
@@ -363,6 +363,83 @@ func TestBytesDecode(t *testing.T) {
 	}
 }
 
+func TestFixedBytesEncode(t *testing.T) {
+	var b20 Bytes20
+	var b32 Bytes32
+	var b256 Bytes256
+	for i := range b256 {
+		b256[i] = byte(i)
+	}
+	copy(b20[:], b256[:])
+	copy(b32[:], b256[:])
+	tests := []struct {
+		data Encoder
+		want []byte
+	}{
+		{Bytes20{}, append([]byte{0x94}, make([]byte, 20)...)},
+		{b20, append([]byte{0x94}, b256[:20]...)},
+		{b32, append([]byte{0xa0}, b256[:32]...)},
+		{b256, append([]byte{0xb9, 0x01, 0x00}, b256[:]...)},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := tt.data.(Sizer).EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestFixedBytesDecode(t *testing.T) {
+	data32 := bytes.Repeat([]byte{0x01}, 32)
+	tests := []struct {
+		data    []byte
+		dest    Decoder
+		want    []byte
+		wantErr error
+	}{
+		{append([]byte{0x94}, data32[:20]...), new(Bytes20), data32[:20], nil},
+		{append([]byte{0xa0}, data32...), new(Bytes32), data32, nil},
+		{append([]byte{0xa0}, data32...), new(Bytes20), nil, ErrInvalidLength},
+		{append([]byte{0x93}, data32[:19]...), new(Bytes20), nil, ErrInvalidLength},
+		{append([]byte{0x9f}, data32[:31]...), new(Bytes32), nil, ErrInvalidLength},
+		{[]byte{0x80}, new(Bytes32), nil, ErrInvalidLength},
+		{[]byte{0xc0}, new(Bytes32), nil, ErrUnsupportedType},
+		{append([]byte{0xa0}, data32[:31]...), new(Bytes32), nil, ErrUnexpectedEndOfData},
+		{append([]byte{0xb9, 0x01, 0x00}, make([]byte, 256)...), new(Bytes256), make([]byte, 256), nil},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			_, err := Decode(tt.data, tt.dest)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			var got []byte
+			switch v := tt.dest.(type) {
+			case *Bytes20:
+				got = v[:]
+			case *Bytes32:
+				got = v[:]
+			case *Bytes256:
+				got = v[:]
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestUintEncode(t *testing.T) {
 	tests := []struct {
 		data uint64
@@ -440,6 +517,232 @@ func TestUintDecode(t *testing.T) {
 	}
 }
 
+func TestFixedWidthUintDecode(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		dest    Decoder
+		want    uint64
+		wantErr error
+	}{
+		{[]byte{0x80}, new(Uint8), 0, nil},
+		{[]byte{0x81, 0xff}, new(Uint8), math.MaxUint8, nil},
+		{[]byte{0x82, 0x01, 0x00}, new(Uint8), 0, ErrTooLarge},
+		{[]byte{0x82, 0xff, 0xff}, new(Uint16), math.MaxUint16, nil},
+		{[]byte{0x83, 0x01, 0x00, 0x00}, new(Uint16), 0, ErrTooLarge},
+		{[]byte{0x84, 0xff, 0xff, 0xff, 0xff}, new(Uint32), math.MaxUint32, nil},
+		{[]byte{0x85, 0x01, 0x00, 0x00, 0x00, 0x00}, new(Uint32), 0, ErrTooLarge},
+		{[]byte{0x82, 0x00, 0x01}, new(Uint16), 0, ErrNonCanonicalEncoding},
+		{[]byte{0x82, 0x00, 0x01}, new(Uint8), 0, ErrTooLarge},
+		{[]byte{0xc0}, new(Uint32), 0, ErrUnsupportedType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			_, err := Decode(tt.data, tt.dest)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			var got uint64
+			switch v := tt.dest.(type) {
+			case *Uint8:
+				got = uint64(v.Get())
+			case *Uint16:
+				got = uint64(v.Get())
+			case *Uint32:
+				got = uint64(v.Get())
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			// The encoding must be the same as the encoding of Uint.
+			enc, err := Encode(tt.dest.(Encoder))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			want, _ := Encode(Uint(tt.want))
+			if !bytes.Equal(enc, want) {
+				t.Fatalf("expected %x, got %x", want, enc)
+			}
+		})
+	}
+}
+
+func TestIntEncode(t *testing.T) {
+	tests := []struct {
+		data int64
+		want []byte
+	}{
+		{0, []byte{0x80}},
+		{-1, []byte{0x01}},
+		{1, []byte{0x02}},
+		{-64, []byte{0x7f}},
+		{64, []byte{0x81, 0x80}},
+		{math.MaxInt64, []byte{0x88, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}},
+		{math.MinInt64, []byte{0x88, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(Int(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := Int(tt.data).EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestIntDecode(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    int64
+		wantErr error
+	}{
+		{[]byte{0x80}, 0, nil},
+		{[]byte{0x01}, -1, nil},
+		{[]byte{0x02}, 1, nil},
+		{[]byte{0x81, 0x80}, 64, nil},
+		{[]byte{0x88, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}, math.MaxInt64, nil},
+		{[]byte{0x88, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, math.MinInt64, nil},
+		{[]byte{0x89, 0x01, 0, 0, 0, 0, 0, 0, 0, 0}, 0, ErrTooLarge},
+		{[]byte{0x82, 0x00, 0x01}, 0, ErrNonCanonicalEncoding},
+		{[]byte{}, 0, ErrUnexpectedEndOfData},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var item Int
+			_, err := Decode(tt.data, &item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if item.Get() != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, item.Get())
+			}
+		})
+	}
+}
+
+func TestTwosComplementIntEncode(t *testing.T) {
+	tests := []struct {
+		data int64
+		want []byte
+	}{
+		{0, []byte{0x80}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{-1, []byte{0x81, 0xff}},
+		{-128, []byte{0x81, 0x80}},
+		{128, []byte{0x82, 0x00, 0x80}},
+		{-129, []byte{0x82, 0xff, 0x7f}},
+		{256, []byte{0x82, 0x01, 0x00}},
+		{math.MaxInt64, []byte{0x88, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{math.MinInt64, []byte{0x88, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(TwosComplementInt(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := TwosComplementInt(tt.data).EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestTwosComplementIntDecode(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    int64
+		wantErr error
+	}{
+		{[]byte{0x80}, 0, nil},
+		{[]byte{0x01}, 1, nil},
+		{[]byte{0x81, 0xff}, -1, nil},
+		{[]byte{0x81, 0x80}, -128, nil},
+		{[]byte{0x82, 0x00, 0x80}, 128, nil},
+		{[]byte{0x82, 0xff, 0x7f}, -129, nil},
+		{[]byte{0x88, 0x80, 0, 0, 0, 0, 0, 0, 0}, math.MinInt64, nil},
+		{[]byte{0x89, 0x00, 0x80, 0, 0, 0, 0, 0, 0, 0}, 0, ErrTooLarge},
+		{[]byte{0x00}, 0, ErrNonCanonicalEncoding},
+		{[]byte{0x82, 0x00, 0x01}, 0, ErrNonCanonicalEncoding},
+		{[]byte{0x82, 0xff, 0x80}, 0, ErrNonCanonicalEncoding},
+		{[]byte{}, 0, ErrUnexpectedEndOfData},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var item TwosComplementInt
+			_, err := Decode(tt.data, &item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if item.Get() != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, item.Get())
+			}
+		})
+	}
+}
+
+func TestBoolEncode(t *testing.T) {
+	tests := []struct {
+		data bool
+		want []byte
+	}{
+		{false, []byte{0x80}},
+		{true, []byte{0x01}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(Bool(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBoolDecode(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    bool
+		wantErr error
+	}{
+		{[]byte{0x80}, false, nil},
+		{[]byte{0x01}, true, nil},
+		{[]byte{}, false, ErrUnexpectedEndOfData},
+		{[]byte{0x00}, false, ErrInvalidBool},
+		{[]byte{0x02}, false, ErrInvalidBool},
+		{[]byte{0x81, 0x01}, false, ErrInvalidBool},
+		{[]byte{0x81, 0x80}, false, ErrInvalidBool},
+		{[]byte{0xc0}, false, ErrInvalidBool},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var item Bool
+			_, err := Decode(tt.data, &item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if item.Get() != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, item.Get())
+			}
+		})
+	}
+}
+
 func TestBigIntEncode(t *testing.T) {
 	tests := []struct {
 		data *big.Int
@@ -496,6 +799,85 @@ func TestBigIntDecode(t *testing.T) {
 	}
 }
 
+func TestBigIntEncodeNegative(t *testing.T) {
+	v := (*BigInt)(big.NewInt(-1))
+	if _, err := Encode(v); !errors.Is(err, ErrNegativeValue) {
+		t.Fatalf("expected ErrNegativeValue, got %v", err)
+	}
+	if _, err := EncodedSize(v); !errors.Is(err, ErrNegativeValue) {
+		t.Fatalf("expected ErrNegativeValue, got %v", err)
+	}
+	if _, err := Encode(List{Uint(1), v}); !errors.Is(err, ErrNegativeValue) {
+		t.Fatalf("expected ErrNegativeValue, got %v", err)
+	}
+}
+
+func TestSignedBigIntEncode(t *testing.T) {
+	tests := []struct {
+		data *big.Int
+		want []byte
+	}{
+		{big.NewInt(0), []byte{0x80}},
+		{big.NewInt(-1), []byte{0x01}},
+		{big.NewInt(1), []byte{0x02}},
+		{big.NewInt(-2), []byte{0x03}},
+		{big.NewInt(63), []byte{0x7e}},
+		{big.NewInt(-64), []byte{0x7f}},
+		{big.NewInt(64), []byte{0x81, 0x80}},
+		{big.NewInt(-129), []byte{0x82, 0x01, 0x01}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			v := (*SignedBigInt)(tt.data)
+			got, err := Encode(v)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := v.EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestSignedBigIntDecode(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    *big.Int
+		wantErr bool
+	}{
+		{[]byte{0x80}, big.NewInt(0), false},
+		{[]byte{0x01}, big.NewInt(-1), false},
+		{[]byte{0x02}, big.NewInt(1), false},
+		{[]byte{0x7f}, big.NewInt(-64), false},
+		{[]byte{0x81, 0x80}, big.NewInt(64), false},
+		{[]byte{0x82, 0x01, 0x01}, big.NewInt(-129), false},
+		{[]byte{}, nil, true},
+		{[]byte{0x82, 0x00, 0x01}, nil, true}, // leading zero
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var item SignedBigInt
+			_, err := Decode(tt.data, &item)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if item.Get().Cmp(tt.want) != 0 {
+				t.Fatalf("expected %v, got %v", tt.want, item.Get())
+			}
+		})
+	}
+}
+
 func TestListEncode(t *testing.T) {
 	tests := []struct {
 		data []any
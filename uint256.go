@@ -0,0 +1,118 @@
+package rlp
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// Uint256 is a 256-bit unsigned integer type that can be encoded and decoded
+// to/from RLP.
+//
+// Unlike BigInt, Uint256 is stored in a fixed-size array, so it can be
+// encoded and decoded without allocating memory. The value is stored as four
+// 64-bit words in little-endian order, that is, u[0] holds the least
+// significant bits.
+type Uint256 [4]uint64
+
+// BigInt returns the value as a big.Int.
+func (u Uint256) BigInt() *big.Int {
+	var b [32]byte
+	return new(big.Int).SetBytes(u.fill(&b))
+}
+
+// SetBigInt sets the value from a big.Int. If the value is negative,
+// ErrNegativeValue is returned. If it does not fit into 256 bits, ErrTooLarge
+// is returned. In both cases, the value is not changed.
+func (u *Uint256) SetBigInt(value *big.Int) error {
+	if value.Sign() < 0 {
+		return ErrNegativeValue
+	}
+	if value.BitLen() > 256 {
+		return ErrTooLarge
+	}
+	var b [32]byte
+	value.FillBytes(b[:])
+	u.setBytes(b[:])
+	return nil
+}
+
+// Uint returns the value as a Uint. If the value does not fit into 64 bits,
+// ErrTooLarge is returned.
+func (u Uint256) Uint() (Uint, error) {
+	if u[1] != 0 || u[2] != 0 || u[3] != 0 {
+		return 0, ErrTooLarge
+	}
+	return Uint(u[0]), nil
+}
+
+// SetUint sets the value from a Uint.
+func (u *Uint256) SetUint(value Uint) {
+	*u = Uint256{uint64(value)}
+}
+
+// EncodeRLP implements the Encoder interface.
+func (u Uint256) EncodeRLP() ([]byte, error) {
+	return u.AppendRLP(nil)
+}
+
+// AppendRLP implements the Appender interface.
+func (u Uint256) AppendRLP(dst []byte) ([]byte, error) {
+	var b [32]byte
+	return appendBytes(dst, u.fill(&b))
+}
+
+// EncodedSize implements the Sizer interface.
+func (u Uint256) EncodedSize() int {
+	var b [32]byte
+	return bytesSize(u.fill(&b))
+}
+
+// DecodeRLP implements the Decoder interface.
+func (u *Uint256) DecodeRLP(data []byte) (int, error) {
+	return u.decodeRLPState(data, nil)
+}
+
+// decodeRLPState implements the stateDecoder interface.
+func (u *Uint256) decodeRLPState(data []byte, s *decodeState) (int, error) {
+	var b []byte
+	n, err := readString(data, &b, s)
+	if err != nil {
+		return 0, decodeError(err, uint256Type)
+	}
+	if b, err = s.canonicalUint(data, b, 32, uint256Type); err != nil {
+		return 0, decodeError(err, uint256Type)
+	}
+	u.setBytes(b)
+	return n, nil
+}
+
+// fill writes the value to b in big-endian order and returns the slice of b
+// without leading zero bytes.
+func (u Uint256) fill(b *[32]byte) []byte {
+	binary.BigEndian.PutUint64(b[0:8], u[3])
+	binary.BigEndian.PutUint64(b[8:16], u[2])
+	binary.BigEndian.PutUint64(b[16:24], u[1])
+	binary.BigEndian.PutUint64(b[24:32], u[0])
+	return b[32-u.byteLen():]
+}
+
+// byteLen returns the number of bytes required to represent the value.
+func (u Uint256) byteLen() int {
+	for i := 3; i >= 0; i-- {
+		if u[i] != 0 {
+			return i*8 + (bits.Len64(u[i])+7)/8
+		}
+	}
+	return 0
+}
+
+// setBytes sets the value from a big-endian integer of at most 32 bytes.
+func (u *Uint256) setBytes(b []byte) {
+	var buf [32]byte
+	copy(buf[32-len(b):], b)
+	u[3] = binary.BigEndian.Uint64(buf[0:8])
+	u[2] = binary.BigEndian.Uint64(buf[8:16])
+	u[1] = binary.BigEndian.Uint64(buf[16:24])
+	u[0] = binary.BigEndian.Uint64(buf[24:32])
+}
@@ -0,0 +1,126 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestUint256Encode(t *testing.T) {
+	tests := []struct {
+		data Uint256
+		want []byte
+	}{
+		{Uint256{}, []byte{0x80}},
+		{Uint256{1}, []byte{0x01}},
+		{Uint256{0x7f}, []byte{0x7f}},
+		{Uint256{0x80}, []byte{0x81, 0x80}},
+		{Uint256{0, 1}, []byte{0x89, 0x01, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{Uint256{0, 0, 0, 1 << 63}, append([]byte{0xa0, 0x80}, make([]byte, 31)...)},
+		{Uint256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}, append([]byte{0xa0}, bytes.Repeat([]byte{0xff}, 32)...)},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+			if size := tt.data.EncodedSize(); size != len(tt.want) {
+				t.Fatalf("expected size %d, got %d", len(tt.want), size)
+			}
+			// The encoding must be the same as the encoding of BigInt.
+			want, err := Encode((*BigInt)(tt.data.BigInt()))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("expected %x, got %x", want, got)
+			}
+		})
+	}
+}
+
+func TestUint256Decode(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    Uint256
+		wantErr error
+	}{
+		{[]byte{0x80}, Uint256{}, nil},
+		{[]byte{0x01}, Uint256{1}, nil},
+		{[]byte{0x81, 0x80}, Uint256{0x80}, nil},
+		{[]byte{0x89, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x02}, Uint256{2, 1}, nil},
+		{append([]byte{0xa0}, bytes.Repeat([]byte{0xff}, 32)...), Uint256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}, nil},
+		{append([]byte{0xa1}, bytes.Repeat([]byte{0xff}, 33)...), Uint256{}, ErrTooLarge},
+		{append([]byte{0xa1, 0x00}, bytes.Repeat([]byte{0xff}, 32)...), Uint256{}, ErrTooLarge},
+		{[]byte{0x82, 0x00, 0x01}, Uint256{}, ErrNonCanonicalEncoding},
+		{[]byte{0x00}, Uint256{}, ErrNonCanonicalEncoding},
+		{[]byte{0xc0}, Uint256{}, ErrUnsupportedType},
+		{[]byte{}, Uint256{}, ErrUnexpectedEndOfData},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var item Uint256
+			_, err := Decode(tt.data, &item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if item != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, item)
+			}
+		})
+	}
+}
+
+func TestUint256Conversions(t *testing.T) {
+	max256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	for n, v := range []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 100), max256} {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var u Uint256
+			if err := u.SetBigInt(v); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if u.BigInt().Cmp(v) != 0 {
+				t.Fatalf("expected %v, got %v", v, u.BigInt())
+			}
+		})
+	}
+	var u Uint256
+	if err := u.SetBigInt(new(big.Int).Add(max256, big.NewInt(1))); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if err := u.SetBigInt(big.NewInt(-1)); !errors.Is(err, ErrNegativeValue) {
+		t.Fatalf("expected ErrNegativeValue, got %v", err)
+	}
+	u.SetUint(42)
+	if v, err := u.Uint(); err != nil || v != 42 {
+		t.Fatalf("expected 42, got %v, %v", v, err)
+	}
+	u = Uint256{0, 1}
+	if _, err := u.Uint(); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestUint256Allocs(t *testing.T) {
+	u := Uint256{1, 2, 3, 4}
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		data, err := u.AppendRLP(buf[:0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v Uint256
+		if _, err := v.DecodeRLP(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}